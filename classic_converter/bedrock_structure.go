@@ -0,0 +1,302 @@
+package classic_converter
+
+import (
+    "io"
+    "math/rand"
+    "strings"
+
+    "github.com/BJTMastermind/Go-MC-Classic-Parser"
+    "github.com/BJTMastermind/go-nbt"
+)
+
+// Block states are written for this Bedrock version (1.18.10), newer versions upgrade them on load
+const bedrockBlockVersion int32 = 17959425
+
+// bedrockBlock is a Bedrock block name and its block states, states can be a string, int8 (bool) or int32.
+type bedrockBlock struct {
+    Name string
+    States map[string]any
+}
+
+var classicBlock2Bedrock = map[int8]bedrockBlock{
+    0: {"minecraft:air", nil},
+    1: {"minecraft:stone", map[string]any{"stone_type": "stone"}},
+    2: {"minecraft:grass", nil},
+    3: {"minecraft:dirt", map[string]any{"dirt_type": "normal"}},
+    4: {"minecraft:cobblestone", nil},
+    5: {"minecraft:planks", map[string]any{"wood_type": "oak"}},
+    6: {"minecraft:sapling", map[string]any{"sapling_type": "oak", "age_bit": int8(0)}},
+    7: {"minecraft:bedrock", map[string]any{"infiniburn_bit": int8(0)}},
+    8: {"minecraft:flowing_water", map[string]any{"liquid_depth": int32(0)}},
+    9: {"minecraft:water", map[string]any{"liquid_depth": int32(0)}},
+    10: {"minecraft:flowing_lava", map[string]any{"liquid_depth": int32(0)}},
+    11: {"minecraft:lava", map[string]any{"liquid_depth": int32(0)}},
+    12: {"minecraft:sand", map[string]any{"sand_type": "normal"}},
+    13: {"minecraft:gravel", nil},
+    14: {"minecraft:gold_ore", nil},
+    15: {"minecraft:iron_ore", nil},
+    16: {"minecraft:coal_ore", nil},
+    17: {"minecraft:log", map[string]any{"old_log_type": "oak", "pillar_axis": "y"}},
+    18: {"minecraft:leaves", map[string]any{"old_leaf_type": "oak", "persistent_bit": int8(1), "update_bit": int8(0)}},
+    19: {"minecraft:sponge", map[string]any{"sponge_type": "dry"}},
+    20: {"minecraft:glass", nil},
+    21: {"minecraft:wool", map[string]any{"color": "red"}},
+    22: {"minecraft:wool", map[string]any{"color": "orange"}},
+    23: {"minecraft:wool", map[string]any{"color": "yellow"}},
+    24: {"minecraft:wool", map[string]any{"color": "lime"}},
+    25: {"minecraft:wool", map[string]any{"color": "green"}},
+    26: {"minecraft:wool", map[string]any{"color": "cyan"}},
+    27: {"minecraft:wool", map[string]any{"color": "light_blue"}},
+    28: {"minecraft:wool", map[string]any{"color": "blue"}},
+    29: {"minecraft:wool", map[string]any{"color": "purple"}},
+    30: {"minecraft:wool", map[string]any{"color": "blue"}},
+    31: {"minecraft:wool", map[string]any{"color": "purple"}},
+    32: {"minecraft:wool", map[string]any{"color": "magenta"}},
+    33: {"minecraft:wool", map[string]any{"color": "pink"}},
+    34: {"minecraft:wool", map[string]any{"color": "black"}},
+    35: {"minecraft:wool", map[string]any{"color": "gray"}},
+    36: {"minecraft:wool", map[string]any{"color": "white"}},
+    37: {"minecraft:yellow_flower", nil},
+    38: {"minecraft:red_flower", map[string]any{"flower_type": "poppy"}},
+    39: {"minecraft:brown_mushroom", nil},
+    40: {"minecraft:red_mushroom", nil},
+    41: {"minecraft:gold_block", nil},
+    42: {"minecraft:iron_block", nil},
+    43: {"minecraft:double_stone_slab", map[string]any{"stone_slab_type": "smooth_stone", "top_slot_bit": int8(0)}},
+    44: {"minecraft:stone_slab", map[string]any{"stone_slab_type": "smooth_stone", "top_slot_bit": int8(0)}},
+    45: {"minecraft:brick_block", nil},
+    46: {"minecraft:tnt", map[string]any{"allow_underwater_bit": int8(0), "explode_bit": int8(0)}},
+    47: {"minecraft:bookshelf", nil},
+    48: {"minecraft:mossy_cobblestone", nil},
+    49: {"minecraft:obsidian", nil},
+}
+
+// BedrockStructure is the Bedrock Edition structure format (.mcstructure), loadable with structure blocks.
+type BedrockStructure struct {
+    Width int16
+    Length int16
+    Height int16
+    Blocks []int8
+    Entities []nbt.Compound
+}
+
+func (bedrock_structure *BedrockStructure) InitWithDefaults() *BedrockStructure {
+    bedrock_structure.Width = 256
+    bedrock_structure.Length = 256
+    bedrock_structure.Height = 64
+    bedrock_structure.Blocks = make([]int8, 256*256*64)
+    bedrock_structure.Entities = []nbt.Compound{}
+
+    return bedrock_structure
+}
+
+func (bedrock_structure *BedrockStructure) FromLevel(level *Level) {
+    bedrock_structure.Width = level.Width
+    bedrock_structure.Length = level.Length
+    bedrock_structure.Height = level.Height
+    bedrock_structure.Blocks = level.Blocks
+
+    compoundEntities := []nbt.Compound{}
+    for _, entity := range level.Mobs() {
+        compoundEntities = append(compoundEntities, ClassicEntity2BedrockCompound(entity))
+    }
+    bedrock_structure.Entities = compoundEntities
+}
+
+func (bedrock_structure *BedrockStructure) WriteTo(w io.Writer) (int64, error) {
+    width := int(bedrock_structure.Width)
+    length := int(bedrock_structure.Length)
+    height := int(bedrock_structure.Height)
+
+    blocks := bedrock_structure.Blocks
+
+    // Every block with the same palette index shares one tag to keep memory use down
+    paletteIndices := map[int8]*nbt.Int{}
+    palette := []nbt.Tag{}
+    noBlock := &nbt.Int{Value: -1}
+
+    primaryLayer := make([]nbt.Tag, width * length * height)
+    secondaryLayer := make([]nbt.Tag, width * length * height)
+
+    // Bedrock orders blocks by x, then y, then z
+    i := 0
+    for x := 0; x < width; x++ {
+        for y := 0; y < height; y++ {
+            for z := 0; z < length; z++ {
+                block := blocks[(y * length + z) * width + x]
+
+                index, ok := paletteIndices[block]
+                if !ok {
+                    index = &nbt.Int{Value: int32(len(palette))}
+                    paletteIndices[block] = index
+                    palette = append(palette, bedrockBlock2Compound(classicBlock2Bedrock[block]))
+                }
+
+                primaryLayer[i] = index
+                secondaryLayer[i] = noBlock
+                i++
+            }
+        }
+    }
+
+    root := nbt.NewCompoundTag("", map[string]nbt.Tag{
+        "format_version": &nbt.Int{
+            Value: 1,
+        },
+        "size": &nbt.List{
+            Value: []nbt.Tag{
+                &nbt.Int{Value: int32(width)},
+                &nbt.Int{Value: int32(height)},
+                &nbt.Int{Value: int32(length)},
+            },
+            ListType: nbt.IDTagInt,
+        },
+        "structure": &nbt.Compound{
+            Value: map[string]nbt.Tag{
+                "block_indices": &nbt.List{
+                    Value: []nbt.Tag{
+                        &nbt.List{
+                            Value: primaryLayer,
+                            ListType: nbt.IDTagInt,
+                        },
+                        &nbt.List{
+                            Value: secondaryLayer,
+                            ListType: nbt.IDTagInt,
+                        },
+                    },
+                    ListType: nbt.IDTagList,
+                },
+                "entities": &nbt.List{
+                    Value: compoundArrayToTagArray(bedrock_structure.Entities),
+                    ListType: ternary[int8](len(bedrock_structure.Entities) == 0, nbt.IDTagEnd, nbt.IDTagCompound),
+                },
+                "palette": &nbt.Compound{
+                    Value: map[string]nbt.Tag{
+                        "default": &nbt.Compound{
+                            Value: map[string]nbt.Tag{
+                                "block_palette": &nbt.List{
+                                    Value: palette,
+                                    ListType: nbt.IDTagCompound,
+                                },
+                                "block_position_data": &nbt.Compound{
+                                    Value: map[string]nbt.Tag{},
+                                },
+                            },
+                        },
+                    },
+                },
+            },
+        },
+        "structure_world_origin": &nbt.List{
+            Value: []nbt.Tag{
+                &nbt.Int{Value: 0},
+                &nbt.Int{Value: 0},
+                &nbt.Int{Value: 0},
+            },
+            ListType: nbt.IDTagInt,
+        },
+    })
+
+    // Bedrock uses uncompressed little endian NBT
+    stream := nbt.NewStream(nbt.LittleEndian)
+
+    err := stream.WriteTag(root)
+    if err != nil {
+        return 0, err
+    }
+
+    n, err := w.Write(stream.Bytes())
+    return int64(n), err
+}
+
+func (bedrock_structure *BedrockStructure) WriteToFile(filename string) error {
+    return writeFile(filename, bedrock_structure)
+}
+
+func bedrockBlock2Compound(block bedrockBlock) *nbt.Compound {
+    states := map[string]nbt.Tag{}
+    for name, value := range block.States {
+        switch value := value.(type) {
+        case string:
+            states[name] = &nbt.String{Value: value}
+        case int8:
+            states[name] = &nbt.Byte{Value: value}
+        case int32:
+            states[name] = &nbt.Int{Value: value}
+        }
+    }
+
+    return &nbt.Compound{
+        Value: map[string]nbt.Tag{
+            "name": &nbt.String{
+                Value: block.Name,
+            },
+            "states": &nbt.Compound{
+                Value: states,
+            },
+            "version": &nbt.Int{
+                Value: bedrockBlockVersion,
+            },
+        },
+    }
+}
+
+func ClassicEntity2BedrockCompound(entity mc_classic_parser.ClassicEntity) nbt.Compound {
+    identifier := "minecraft:" + strings.ToLower(textureName2Id(entity.TextureName))
+
+    output := nbt.Compound{
+        Value: map[string]nbt.Tag{
+            "identifier": &nbt.String{
+                Value: identifier,
+            },
+            "definitions": &nbt.List{
+                Value: []nbt.Tag{
+                    &nbt.String{Value: "+" + identifier},
+                },
+                ListType: nbt.IDTagString,
+            },
+            "UniqueID": &nbt.Long{
+                Value: rand.Int63(),
+            },
+            "Pos": &nbt.List{
+                Value: []nbt.Tag{
+                    &nbt.Float{Value: entity.X},
+                    &nbt.Float{Value: entity.Y},
+                    &nbt.Float{Value: entity.Z},
+                },
+                ListType: nbt.IDTagFloat,
+            },
+            "Rotation": &nbt.List{
+                Value: []nbt.Tag{
+                    &nbt.Float{Value: entity.YRot},
+                    &nbt.Float{Value: entity.XRot},
+                },
+                ListType: nbt.IDTagFloat,
+            },
+            "Motion": &nbt.List{
+                Value: []nbt.Tag{
+                    &nbt.Float{Value: entity.Xd},
+                    &nbt.Float{Value: entity.Yd},
+                    &nbt.Float{Value: entity.Zd},
+                },
+                ListType: nbt.IDTagFloat,
+            },
+            "FallDistance": &nbt.Float{
+                Value: entity.FallDistance,
+            },
+            "OnGround": &nbt.Byte{
+                Value: ternary[int8](entity.OnGround, 1, 0),
+            },
+            "Persistent": &nbt.Byte{
+                Value: 1,
+            },
+        },
+    }
+    if identifier == "minecraft:sheep" {
+        output.Value["Sheared"] = &nbt.Byte{
+            Value: ternary[int8](entity.HasHair, 0, 1),
+        }
+    }
+
+    return output
+}
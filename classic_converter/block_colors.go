@@ -0,0 +1,90 @@
+package classic_converter
+
+import (
+    "image/color"
+)
+
+// Highest block ID with a known color, classic 0.30 blocks followed by the CPE CustomBlocks
+const classicMaxColoredBlock int8 = 65
+
+// Average color of each block's top texture in classic's terrain.png, liquids, glass and ice are see-through.
+// Colors are not premultiplied, so they can be used with any alpha.
+var classicBlockColors = map[int8]color.NRGBA{
+    0: {0, 0, 0, 0}, // Air
+    1: {125, 125, 125, 255}, // Stone
+    2: {117, 176, 73, 255}, // Grass
+    3: {134, 96, 67, 255}, // Dirt
+    4: {122, 122, 122, 255}, // Cobblestone
+    5: {157, 128, 79, 255}, // Planks
+    6: {71, 102, 37, 255}, // Sapling
+    7: {84, 84, 84, 255}, // Bedrock
+    8: {39, 67, 173, 160}, // Flowing Water
+    9: {39, 67, 173, 160}, // Still Water
+    10: {212, 90, 18, 255}, // Flowing Lava
+    11: {212, 90, 18, 255}, // Still Lava
+    12: {219, 211, 160, 255}, // Sand
+    13: {136, 126, 126, 255}, // Gravel
+    14: {143, 140, 125, 255}, // Gold Ore
+    15: {136, 130, 127, 255}, // Iron Ore
+    16: {115, 115, 115, 255}, // Coal Ore
+    17: {102, 81, 51, 255}, // Log
+    18: {64, 145, 38, 255}, // Leaves
+    19: {195, 195, 78, 255}, // Sponge
+    20: {218, 240, 244, 80}, // Glass
+    21: {222, 50, 50, 255}, // Red Cloth
+    22: {222, 136, 50, 255}, // Orange Cloth
+    23: {222, 222, 50, 255}, // Yellow Cloth
+    24: {136, 222, 50, 255}, // Lime Cloth
+    25: {50, 222, 50, 255}, // Green Cloth
+    26: {50, 222, 136, 255}, // Aqua Green Cloth
+    27: {50, 222, 222, 255}, // Cyan Cloth
+    28: {104, 163, 222, 255}, // Blue Cloth
+    29: {120, 120, 222, 255}, // Purple Cloth
+    30: {136, 50, 222, 255}, // Indigo Cloth
+    31: {174, 74, 222, 255}, // Violet Cloth
+    32: {222, 50, 222, 255}, // Magenta Cloth
+    33: {222, 50, 136, 255}, // Pink Cloth
+    34: {77, 77, 77, 255}, // Black Cloth
+    35: {150, 150, 150, 255}, // Gray Cloth
+    36: {222, 222, 222, 255}, // White Cloth
+    37: {205, 200, 40, 255}, // Dandelion
+    38: {180, 30, 20, 255}, // Rose
+    39: {145, 109, 85, 255}, // Brown Mushroom
+    40: {196, 42, 44, 255}, // Red Mushroom
+    41: {249, 236, 78, 255}, // Gold Block
+    42: {219, 219, 219, 255}, // Iron Block
+    43: {168, 168, 168, 255}, // Double Slab
+    44: {168, 168, 168, 255}, // Slab
+    45: {146, 99, 86, 255}, // Bricks
+    46: {160, 83, 65, 255}, // TNT
+    47: {108, 88, 58, 255}, // Bookshelf
+    48: {104, 120, 93, 255}, // Mossy Cobblestone
+    49: {20, 18, 29, 255}, // Obsidian
+    // CPE CustomBlocks
+    50: {122, 122, 122, 255}, // Cobblestone Slab
+    51: {140, 110, 70, 255}, // Rope
+    52: {216, 203, 155, 255}, // Sandstone
+    53: {240, 251, 251, 255}, // Snow
+    54: {230, 140, 30, 255}, // Fire
+    55: {245, 175, 200, 255}, // Light Pink Cloth
+    56: {40, 100, 40, 255}, // Forest Green Cloth
+    57: {110, 75, 40, 255}, // Brown Cloth
+    58: {30, 45, 160, 255}, // Deep Blue Cloth
+    59: {50, 160, 170, 255}, // Turquoise Cloth
+    60: {160, 190, 250, 180}, // Ice
+    61: {200, 200, 200, 255}, // Ceramic Tile
+    62: {140, 60, 30, 255}, // Magma
+    63: {230, 226, 218, 255}, // Pillar
+    64: {150, 110, 60, 255}, // Crate
+    65: {120, 120, 120, 255}, // Stone Brick
+}
+
+// Returns the color of the given block, unknown blocks get the color of the block the cpe table writes them as
+func ClassicBlockColor(block int8) color.NRGBA {
+    if blockColor, ok := classicBlockColors[block]; ok {
+        return blockColor
+    }
+
+    exported, _ := blockTables["cpe"].exportBlock(block)
+    return classicBlockColors[exported.ID]
+}
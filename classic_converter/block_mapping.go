@@ -0,0 +1,632 @@
+package classic_converter
+
+import (
+    "encoding/json"
+    "fmt"
+    "os"
+    "path/filepath"
+    "sort"
+    "strconv"
+    "strings"
+
+    "github.com/BurntSushi/toml"
+)
+
+// Levels keep their blocks as classic 0.30 IDs followed by the CPE CustomBlocks (0 - 65), called the converter's blocks here.
+// A BlockTable says how a version numbers those blocks, every format is written with one and some are read with one.
+type BlockTable struct {
+    Name string
+    Description string
+    MaxBlock int8 // Converter blocks up to this ID have the same ID in the version, unless Export says otherwise
+    Export map[int8]TableBlock // Converter blocks the version has under another ID or data value
+    Import map[TableBlock]int8 // Blocks of the version that aren't the converter block with the same ID, Data -1 matches any data value
+}
+
+// TableBlock is a block of a version, the data value picks between blocks sharing an ID like wool colors
+type TableBlock struct {
+    ID int8
+    Data int8
+}
+
+// Data value of Import keys that match every data value of the ID
+const anyData int8 = -1
+
+func (table_block TableBlock) String() string {
+    if table_block.Data == 0 {
+        return strconv.Itoa(int(table_block.ID))
+    }
+    return fmt.Sprintf("%d:%d", table_block.ID, table_block.Data)
+}
+
+var blockTables = map[string]*BlockTable{}
+
+func init() {
+    tables := []*BlockTable{
+        {
+            Name: "cpe",
+            Description: "classic 0.30 with the CPE CustomBlocks",
+            MaxBlock: 65,
+        },
+        {
+            Name: "pre_classic",
+            Description: "pre-classic (rd-132211 - 0.0.12a)",
+            MaxBlock: 11, // Still Lava, the newest block before sand and gravel came in 0.0.13a
+        },
+        {
+            Name: "indev",
+            Description: "Indev",
+            MaxBlock: 49,
+            Export: map[int8]TableBlock{
+                54: {51, 0}, // Fire
+            },
+            Import: map[TableBlock]int8{
+                {50, anyData}: 0, // Torch -> Air
+                {51, anyData}: 54, // Fire
+                {52, anyData}: 9, // Infinite Water Source -> Water
+                {53, anyData}: 11, // Infinite Lava Source -> Lava
+                {54, anyData}: 64, // Chest -> Crate
+                {55, anyData}: 0, // Gear -> Air
+                {56, anyData}: 15, // Diamond Ore -> Iron Ore
+                {57, anyData}: 42, // Diamond Block -> Iron Block
+                {58, anyData}: 64, // Workbench -> Crate
+                {59, anyData}: 6, // Crops -> Sapling
+                {60, anyData}: 3, // Farmland -> Dirt
+                {61, anyData}: 4, // Furnace -> Cobblestone
+                {62, anyData}: 4, // Lit Furnace -> Cobblestone
+            },
+        },
+        {
+            Name: "alpha",
+            Description: "Alpha and Beta (MCEdit's Alpha materials)",
+            MaxBlock: 49,
+            Export: map[int8]TableBlock{
+                21: {35, 14}, // Red Cloth -> Red Wool
+                22: {35, 1}, // Orange Cloth -> Orange Wool
+                23: {35, 4}, // Yellow Cloth -> Yellow Wool
+                24: {35, 5}, // Lime Cloth -> Lime Wool
+                25: {35, 13}, // Green Cloth -> Green Wool
+                26: {35, 9}, // Aqua Green Cloth -> Cyan Wool
+                27: {35, 3}, // Cyan Cloth -> Light Blue Wool
+                28: {35, 11}, // Blue Cloth -> Blue Wool
+                29: {35, 10}, // Purple Cloth -> Purple Wool
+                30: {35, 11}, // Indigo Cloth -> Blue Wool
+                31: {35, 10}, // Violet Cloth -> Purple Wool
+                32: {35, 2}, // Magenta Cloth -> Magenta Wool
+                33: {35, 6}, // Pink Cloth -> Pink Wool
+                34: {35, 15}, // Black Cloth -> Black Wool
+                35: {35, 7}, // Gray Cloth -> Gray Wool
+                36: {35, 0}, // White Cloth -> White Wool
+                50: {44, 3}, // Cobblestone Slab
+                51: {65, 0}, // Rope -> Ladder
+                52: {24, 0}, // Sandstone
+                53: {78, 0}, // Snow
+                54: {51, 0}, // Fire
+                55: {35, 6}, // Light Pink Cloth -> Pink Wool
+                56: {35, 13}, // Forest Green Cloth -> Green Wool
+                57: {35, 12}, // Brown Cloth -> Brown Wool
+                58: {35, 11}, // Deep Blue Cloth -> Blue Wool
+                59: {35, 9}, // Turquoise Cloth -> Cyan Wool
+                60: {79, 0}, // Ice
+                61: {43, 0}, // Ceramic Tile -> Double Slab
+                62: {87, 0}, // Magma -> Netherrack
+                63: {35, 0}, // Pillar -> White Wool
+                64: {5, 0}, // Crate -> Planks
+                65: {98, 0}, // Stone Brick -> Stone Bricks
+            },
+            Import: map[TableBlock]int8{
+                {21, anyData}: 1, // Lapis Ore -> Stone
+                {22, anyData}: 28, // Lapis Block -> Blue Cloth
+                {23, anyData}: 4, // Dispenser -> Cobblestone
+                {24, anyData}: 52, // Sandstone
+                {25, anyData}: 5, // Note Block -> Planks
+                {26, anyData}: 0, // Bed -> Air
+                {27, anyData}: 0, // Powered Rail -> Air
+                {28, anyData}: 0, // Detector Rail -> Air
+                {29, anyData}: 5, // Sticky Piston -> Planks
+                {30, anyData}: 0, // Cobweb -> Air
+                {31, anyData}: 0, // Tall Grass -> Air
+                {32, anyData}: 0, // Dead Bush -> Air
+                {33, anyData}: 5, // Piston -> Planks
+                {34, anyData}: 0, // Piston Head -> Air
+                {35, 0}: 36, // White Wool
+                {35, 1}: 22, // Orange Wool
+                {35, 2}: 32, // Magenta Wool
+                {35, 3}: 27, // Light Blue Wool -> Cyan Cloth
+                {35, 4}: 23, // Yellow Wool
+                {35, 5}: 24, // Lime Wool
+                {35, 6}: 33, // Pink Wool
+                {35, 7}: 35, // Gray Wool
+                {35, 8}: 35, // Light Gray Wool -> Gray Cloth
+                {35, 9}: 26, // Cyan Wool -> Aqua Green Cloth
+                {35, 10}: 29, // Purple Wool
+                {35, 11}: 28, // Blue Wool
+                {35, 12}: 57, // Brown Wool
+                {35, 13}: 25, // Green Wool
+                {35, 14}: 21, // Red Wool
+                {35, 15}: 34, // Black Wool
+                {44, 3}: 50, // Cobblestone Slab
+                {50, anyData}: 0, // Torch -> Air
+                {51, anyData}: 54, // Fire
+                {52, anyData}: 0, // Mob Spawner -> Air
+                {53, anyData}: 5, // Wooden Stairs -> Planks
+                {54, anyData}: 64, // Chest -> Crate
+                {55, anyData}: 0, // Redstone Wire -> Air
+                {56, anyData}: 15, // Diamond Ore -> Iron Ore
+                {57, anyData}: 42, // Diamond Block -> Iron Block
+                {58, anyData}: 64, // Crafting Table -> Crate
+                {59, anyData}: 6, // Wheat -> Sapling
+                {60, anyData}: 3, // Farmland -> Dirt
+                {61, anyData}: 4, // Furnace -> Cobblestone
+                {62, anyData}: 4, // Lit Furnace -> Cobblestone
+                {63, anyData}: 0, // Sign -> Air
+                {64, anyData}: 0, // Wooden Door -> Air
+                {65, anyData}: 51, // Ladder -> Rope
+                {66, anyData}: 0, // Rail -> Air
+                {67, anyData}: 4, // Cobblestone Stairs -> Cobblestone
+                {68, anyData}: 0, // Wall Sign -> Air
+                {69, anyData}: 0, // Lever -> Air
+                {70, anyData}: 0, // Stone Pressure Plate -> Air
+                {71, anyData}: 0, // Iron Door -> Air
+                {72, anyData}: 0, // Wooden Pressure Plate -> Air
+                {73, anyData}: 1, // Redstone Ore -> Stone
+                {74, anyData}: 1, // Lit Redstone Ore -> Stone
+                {75, anyData}: 0, // Redstone Torch -> Air
+                {76, anyData}: 0, // Lit Redstone Torch -> Air
+                {77, anyData}: 0, // Stone Button -> Air
+                {78, anyData}: 53, // Snow
+                {79, anyData}: 60, // Ice
+                {80, anyData}: 36, // Snow Block -> White Cloth
+                {81, anyData}: 25, // Cactus -> Green Cloth
+                {82, anyData}: 13, // Clay -> Gravel
+                {83, anyData}: 6, // Sugar Cane -> Sapling
+                {84, anyData}: 5, // Jukebox -> Planks
+                {85, anyData}: 5, // Fence -> Planks
+                {86, anyData}: 22, // Pumpkin -> Orange Cloth
+                {87, anyData}: 62, // Netherrack -> Magma
+                {88, anyData}: 3, // Soul Sand -> Dirt
+                {89, anyData}: 23, // Glowstone -> Yellow Cloth
+                {90, anyData}: 0, // Portal -> Air
+                {91, anyData}: 22, // Jack o'Lantern -> Orange Cloth
+                {92, anyData}: 0, // Cake -> Air
+                {93, anyData}: 0, // Repeater -> Air
+                {94, anyData}: 0, // Lit Repeater -> Air
+                {95, anyData}: 64, // Locked Chest -> Crate
+                {96, anyData}: 0, // Trapdoor -> Air
+                {97, anyData}: 1, // Monster Egg -> Stone
+                {98, anyData}: 65, // Stone Bricks -> Stone Brick
+            },
+        },
+    }
+    // Every classic version only has the blocks up to its newest one
+    for _, version := range ClassicVersions() {
+        maxBlock, _ := ClassicMaxBlock(version)
+        tables = append(tables, &BlockTable{Name: version, Description: "classic " + version, MaxBlock: maxBlock})
+    }
+
+    for _, table := range tables {
+        blockTables[table.Name] = table
+    }
+}
+
+// Returns the names of every block table, sorted
+func BlockTables() []string {
+    names := make([]string, 0, len(blockTables))
+    for name := range blockTables {
+        names = append(names, name)
+    }
+    sort.Strings(names)
+    return names
+}
+
+func BlockTableByName(name string) (*BlockTable, bool) {
+    table, ok := blockTables[name]
+    return table, ok
+}
+
+func isBlockTable(name string) bool {
+    _, ok := blockTables[name]
+    return ok
+}
+
+// Returns the block of the version the converter block is written as, and if it is the same block
+// so its data value is kept. Blocks the version doesn't have fall back to older looking blocks until one it has is found.
+func (block_table *BlockTable) exportBlock(block int8) (TableBlock, bool) {
+    exported, same, _ := block_table.lookupBlock(block)
+    return exported, same
+}
+
+// Same as exportBlock, and also says if the table had the block or something to fall back to instead of stone
+func (block_table *BlockTable) lookupBlock(block int8) (TableBlock, bool, bool) {
+    fellBack := false
+    for {
+        if exported, ok := block_table.Export[block]; ok {
+            return exported, false, true
+        }
+        if block >= 0 && block <= block_table.MaxBlock {
+            return TableBlock{block, 0}, !fellBack, true
+        }
+
+        fallback, ok := classicBlockFallback[block]
+        if !ok || fallback >= block {
+            return TableBlock{1, 0}, false, false // Stone
+        }
+        block = fallback
+        fellBack = true
+    }
+}
+
+// Returns the highest ID the table writes blocks as
+func (block_table *BlockTable) highestBlock() int8 {
+    highest := block_table.MaxBlock
+    for _, exported := range block_table.Export {
+        if exported.ID > highest {
+            highest = exported.ID
+        }
+    }
+    return highest
+}
+
+// Returns the converter block the block of the version is read as, and if its data value was used up picking it.
+// Blocks the table doesn't know about are read as stone.
+func (block_table *BlockTable) importBlock(block TableBlock) (int8, bool) {
+    if imported, ok := block_table.Import[block]; ok {
+        return imported, true
+    }
+    if imported, ok := block_table.Import[TableBlock{block.ID, anyData}]; ok {
+        return imported, false
+    }
+    if block.ID >= 0 && block.ID <= block_table.MaxBlock {
+        return block.ID, false
+    }
+    return 1, false // Stone
+}
+
+// blockRemap counts the blocks that became another block on the way in or out of a table
+type blockRemap map[blockRemapKey]int
+
+type blockRemapKey struct {
+    Block int8 // Converter block
+    TableBlock TableBlock
+    Override bool // The block overrides asked for it
+}
+
+// Logs what got remapped writing the table, blocks that read back as the same block were only renumbered and are left to verbose logging
+func (block_remap blockRemap) logExport(table *BlockTable) {
+    for _, key := range block_remap.sortedKeys() {
+        count := block_remap[key]
+        readBack, _ := table.importBlock(key.TableBlock)
+        switch {
+        case key.Override:
+            logInfo("Replaced %d %s blocks (ID %d) with %s (ID %s) for %s, as the block overrides say", count, ClassicBlockName(key.Block), key.Block, ClassicBlockName(readBack), key.TableBlock, table.Description)
+        case readBack == key.Block:
+            logDebug("Renumbered blocks", map[string]any{"table": table.Name, "block": ClassicBlockName(key.Block), "from": key.Block, "to": key.TableBlock.String(), "count": count})
+        default:
+            logWarn("Remapped %d %s blocks (ID %d) to %s (ID %s), not available in %s", count, ClassicBlockName(key.Block), key.Block, ClassicBlockName(readBack), key.TableBlock, table.Description)
+        }
+    }
+}
+
+// Logs what got remapped reading the table, blocks that would be written back the same way were only renumbered
+func (block_remap blockRemap) logImport(table *BlockTable) {
+    for _, key := range block_remap.sortedKeys() {
+        count := block_remap[key]
+        writtenBack, _ := table.exportBlock(key.Block)
+        if writtenBack == key.TableBlock {
+            logDebug("Renumbered blocks", map[string]any{"table": table.Name, "block": ClassicBlockName(key.Block), "from": key.TableBlock.String(), "to": key.Block, "count": count})
+            continue
+        }
+        logWarn("Mapped %d blocks of %s ID %s to %s (ID %d), the closest block there is", count, table.Description, key.TableBlock, ClassicBlockName(key.Block), key.Block)
+    }
+}
+
+func (block_remap blockRemap) sortedKeys() []blockRemapKey {
+    keys := make([]blockRemapKey, 0, len(block_remap))
+    for key := range block_remap {
+        keys = append(keys, key)
+    }
+    sort.Slice(keys, func(i, j int) bool {
+        if keys[i].Block != keys[j].Block {
+            return keys[i].Block < keys[j].Block
+        }
+        if keys[i].TableBlock.ID != keys[j].TableBlock.ID {
+            return keys[i].TableBlock.ID < keys[j].TableBlock.ID
+        }
+        return keys[i].TableBlock.Data < keys[j].TableBlock.Data
+    })
+    return keys
+}
+
+// Returns the blocks and data values written with the table after the overrides are applied, data is nil when
+// there was none to begin with and no block needs one. The given slices are left as they are.
+func (block_table *BlockTable) exportBlocks(blocks []int8, data []int8, overrides map[int8]int8) ([]int8, []int8, blockRemap) {
+    remap := blockRemap{}
+    mapped := make([]int8, len(blocks))
+    var mappedData []int8
+    if len(data) == len(blocks) {
+        mappedData = append([]int8{}, data...)
+    }
+
+    for i, block := range blocks {
+        replacement, overridden := overrides[block]
+        if !overridden {
+            replacement = block
+        }
+        exported, same := block_table.exportBlock(replacement)
+        mapped[i] = exported.ID
+        if same && !overridden {
+            continue
+        }
+
+        if mappedData == nil && exported.Data != 0 {
+            mappedData = make([]int8, len(blocks))
+        }
+        if mappedData != nil {
+            mappedData[i] = exported.Data
+        }
+        remap[blockRemapKey{block, exported, overridden}]++
+    }
+    return mapped, mappedData, remap
+}
+
+// Maps the level's blocks from the table to the converter's blocks, data values that picked the block are cleared
+func (block_table *BlockTable) importLevel(level *Level) {
+    remap := blockRemap{}
+    hasData := len(level.Data) == len(level.Blocks)
+    for i, block := range level.Blocks {
+        tableBlock := TableBlock{block, 0}
+        if hasData {
+            tableBlock.Data = level.Data[i]
+        }
+        imported, usedData := block_table.importBlock(tableBlock)
+        if imported == block && !usedData {
+            continue
+        }
+
+        level.Blocks[i] = imported
+        if hasData {
+            level.Data[i] = 0
+        }
+        remap[blockRemapKey{imported, TableBlock{tableBlock.ID, ternary(usedData, tableBlock.Data, 0)}, false}]++
+    }
+    remap.logImport(block_table)
+}
+
+// MapBlocks returns a copy of the level with its blocks mapped to the table the format is written with, and logs what got remapped.
+// Writers get filled in from the copy, so every format gets its blocks the same way. Blocks the table has nothing for become
+// stone, with options.StrictBlocks they are an ErrUnmappableBlock error instead.
+func (format Format) MapBlocks(level *Level, options Options) (*Level, error) {
+    table, ok := BlockTableByName(format.blockTableName(options))
+    if !ok {
+        return nil, newError(ErrInvalidOption, format.Name, nil, "error: Unknown block table %s. Expected one of %s", format.blockTableName(options), strings.Join(BlockTables(), ", "))
+    }
+
+    overrides := options.BlockOverrides.forTable(table.Name)
+    if options.StrictBlocks {
+        checked := map[int8]bool{}
+        for _, block := range level.Blocks {
+            if checked[block] {
+                continue
+            }
+            checked[block] = true
+
+            replacement, overridden := overrides[block]
+            if !overridden {
+                replacement = block
+            }
+            if _, _, found := table.lookupBlock(replacement); !found {
+                return nil, newError(ErrUnmappableBlock, format.Name, nil, "error: %s (ID %d) has no counterpart in %s.", ClassicBlockName(replacement), replacement, table.Description)
+            }
+        }
+    }
+
+    mapped := *level
+    var remap blockRemap
+    mapped.Blocks, mapped.Data, remap = table.exportBlocks(level.Blocks, level.Data, overrides)
+    remap.logExport(table)
+    return &mapped, nil
+}
+
+// Picks the table the format is written with. A target version picks the table of formats written for a classic version.
+func (format Format) blockTableName(options Options) string {
+    if options.BlockTable != "" {
+        return options.BlockTable
+    }
+    if _, classic := ClassicMaxBlock(format.BlockTable); classic && options.TargetVersion != "" {
+        return options.TargetVersion
+    }
+    if format.BlockTable != "" {
+        return format.BlockTable
+    }
+    return "cpe"
+}
+
+// BlockOverrides are blocks swapped for other blocks before they are mapped to a table, for every table or only for one.
+// Overrides name converter blocks both ways, so the table still decides how the replacement is numbered.
+type BlockOverrides struct {
+    Blocks map[int8]int8
+    Tables map[string]map[int8]int8 // By table name, used instead of Blocks for the same block
+}
+
+// Returns the overrides used for the table
+func (block_overrides *BlockOverrides) forTable(table string) map[int8]int8 {
+    if block_overrides == nil {
+        return nil
+    }
+    overrides := map[int8]int8{}
+    for block, replacement := range block_overrides.Blocks {
+        overrides[block] = replacement
+    }
+    for block, replacement := range block_overrides.Tables[table] {
+        overrides[block] = replacement
+    }
+    return overrides
+}
+
+// ReadBlockOverrides reads overrides from a TOML (by its .toml extension) or JSON file laid out as
+//
+//    [blocks]
+//    sandstone = "sand"
+//    60 = 20
+//
+//    [tables.indev]
+//    rope = "air"
+//
+// Blocks are named by their ID, their name or their flattened name like "minecraft:red_wool".
+func ReadBlockOverrides(filename string) (*BlockOverrides, error) {
+    content, err := os.ReadFile(filename)
+    if err != nil {
+        return nil, newError(ErrInvalidOption, "", err, "error: Could not read the block overrides %s, %s.", filename, err)
+    }
+
+    var file struct {
+        Blocks map[string]any `json:"blocks" toml:"blocks"`
+        Tables map[string]map[string]any `json:"tables" toml:"tables"`
+    }
+    if strings.ToLower(filepath.Ext(filename)) == ".toml" {
+        err = toml.Unmarshal(content, &file)
+    } else {
+        err = json.Unmarshal(content, &file)
+    }
+    if err != nil {
+        return nil, newError(ErrInvalidOption, "", err, "error: The block overrides %s are not vaild, %s.", filename, err)
+    }
+
+    overrides := &BlockOverrides{Tables: map[string]map[int8]int8{}}
+    overrides.Blocks, err = parseBlockOverrides(file.Blocks)
+    if err != nil {
+        return nil, err
+    }
+    for table, blocks := range file.Tables {
+        if _, ok := BlockTableByName(table); !ok {
+            return nil, newError(ErrInvalidOption, "", nil, "error: Unknown block table %s in %s. Expected one of %s", table, filename, strings.Join(BlockTables(), ", "))
+        }
+        overrides.Tables[table], err = parseBlockOverrides(blocks)
+        if err != nil {
+            return nil, err
+        }
+    }
+    return overrides, nil
+}
+
+func parseBlockOverrides(blocks map[string]any) (map[int8]int8, error) {
+    overrides := map[int8]int8{}
+    for name, replacementName := range blocks {
+        block, ok := BlockByName(name)
+        if !ok {
+            return nil, newError(ErrInvalidOption, "", nil, "error: Unknown block %s in the block overrides.", name)
+        }
+        replacement, ok := BlockByName(fmt.Sprint(replacementName))
+        if !ok {
+            return nil, newError(ErrInvalidOption, "", nil, "error: Unknown block %v in the block overrides.", replacementName)
+        }
+        overrides[block] = replacement
+    }
+    return overrides, nil
+}
+
+// BlockByName finds the converter block with the given ID, name (see ClassicBlockName) or flattened name
+func BlockByName(name string) (int8, bool) {
+    name = strings.ToLower(strings.TrimSpace(name))
+    if id, err := strconv.Atoi(name); err == nil {
+        if id < 0 || id > 127 {
+            return 0, false
+        }
+        _, known := classicBlockNames[int8(id)]
+        return int8(id), known
+    }
+    for block, blockName := range classicBlockNames {
+        if blockName == name {
+            return block, true
+        }
+    }
+
+    // Flattened names are shared by some blocks, the one with the lowest ID is picked
+    found := false
+    var lowest int8
+    for block, blockName := range flattenedBlockNames {
+        if (blockName == name || blockName == "minecraft:" + name) && (!found || block < lowest) {
+            found = true
+            lowest = block
+        }
+    }
+    return lowest, found
+}
+
+// Names the converter's blocks have in Java Edition since the flattening (1.13), only used to name blocks as no format is written with them
+var flattenedBlockNames = map[int8]string{
+    0: "minecraft:air",
+    1: "minecraft:stone",
+    2: "minecraft:grass_block",
+    3: "minecraft:dirt",
+    4: "minecraft:cobblestone",
+    5: "minecraft:oak_planks",
+    6: "minecraft:oak_sapling",
+    7: "minecraft:bedrock",
+    8: "minecraft:water",
+    9: "minecraft:water",
+    10: "minecraft:lava",
+    11: "minecraft:lava",
+    12: "minecraft:sand",
+    13: "minecraft:gravel",
+    14: "minecraft:gold_ore",
+    15: "minecraft:iron_ore",
+    16: "minecraft:coal_ore",
+    17: "minecraft:oak_log",
+    18: "minecraft:oak_leaves",
+    19: "minecraft:sponge",
+    20: "minecraft:glass",
+    21: "minecraft:red_wool",
+    22: "minecraft:orange_wool",
+    23: "minecraft:yellow_wool",
+    24: "minecraft:lime_wool",
+    25: "minecraft:green_wool",
+    26: "minecraft:cyan_wool",
+    27: "minecraft:light_blue_wool",
+    28: "minecraft:blue_wool",
+    29: "minecraft:purple_wool",
+    30: "minecraft:blue_wool",
+    31: "minecraft:purple_wool",
+    32: "minecraft:magenta_wool",
+    33: "minecraft:pink_wool",
+    34: "minecraft:black_wool",
+    35: "minecraft:gray_wool",
+    36: "minecraft:white_wool",
+    37: "minecraft:dandelion",
+    38: "minecraft:poppy",
+    39: "minecraft:brown_mushroom",
+    40: "minecraft:red_mushroom",
+    41: "minecraft:gold_block",
+    42: "minecraft:iron_block",
+    43: "minecraft:smooth_stone",
+    44: "minecraft:smooth_stone_slab",
+    45: "minecraft:bricks",
+    46: "minecraft:tnt",
+    47: "minecraft:bookshelf",
+    48: "minecraft:mossy_cobblestone",
+    49: "minecraft:obsidian",
+    // CPE CustomBlocks
+    50: "minecraft:cobblestone_slab",
+    51: "minecraft:ladder",
+    52: "minecraft:sandstone",
+    53: "minecraft:snow",
+    54: "minecraft:fire",
+    55: "minecraft:pink_wool",
+    56: "minecraft:green_wool",
+    57: "minecraft:brown_wool",
+    58: "minecraft:blue_wool",
+    59: "minecraft:cyan_wool",
+    60: "minecraft:ice",
+    61: "minecraft:smooth_stone",
+    62: "minecraft:netherrack",
+    63: "minecraft:quartz_pillar",
+    64: "minecraft:oak_planks",
+    65: "minecraft:stone_bricks",
+}
+
+// Returns the flattened name of the given block, unknown blocks are named after their ID
+func FlattenedBlockName(block int8) string {
+    if name, ok := flattenedBlockNames[block]; ok {
+        return name
+    }
+    return fmt.Sprintf("minecraft:block_%d", block)
+}
@@ -0,0 +1,154 @@
+package classic_converter
+
+import (
+    "errors"
+    "reflect"
+    "testing"
+)
+
+func TestExportBlocksCountsRemaps(t *testing.T) {
+    tests := []struct {
+        name string
+        table string
+        blocks []int8
+        overrides map[int8]int8
+        mapped []int8
+        data []int8
+        remap blockRemap
+    }{
+        {
+            name: "blocks the table has are kept",
+            table: "0.30",
+            blocks: []int8{0, 1, 2, 49},
+            mapped: []int8{0, 1, 2, 49},
+            remap: blockRemap{},
+        },
+        {
+            name: "fallbacks are counted",
+            table: "0.0.23a",
+            blocks: []int8{52, 52, 49, 1},
+            mapped: []int8{12, 12, 1, 1},
+            remap: blockRemap{
+                {52, TableBlock{12, 0}, false}: 2, // Sandstone -> Sand
+                {49, TableBlock{1, 0}, false}: 1, // Obsidian -> Stone
+            },
+        },
+        {
+            name: "chained fallbacks are counted once",
+            table: "0.0.13a",
+            blocks: []int8{41, 46, 3},
+            mapped: []int8{1, 4, 3},
+            remap: blockRemap{
+                {41, TableBlock{1, 0}, false}: 1, // Gold Block -> Gold Ore -> Stone
+                {46, TableBlock{4, 0}, false}: 1, // TNT -> Bricks -> Cobblestone
+            },
+        },
+        {
+            name: "blocks outside every table become stone",
+            table: "cpe",
+            blocks: []int8{-5, 66, 65},
+            mapped: []int8{1, 1, 65},
+            remap: blockRemap{
+                {-5, TableBlock{1, 0}, false}: 1,
+                {66, TableBlock{1, 0}, false}: 1,
+            },
+        },
+        {
+            name: "exported blocks are counted with their data",
+            table: "alpha",
+            blocks: []int8{21, 21, 1},
+            mapped: []int8{35, 35, 1},
+            data: []int8{14, 14, 0},
+            remap: blockRemap{
+                {21, TableBlock{35, 14}, false}: 2, // Red Cloth -> Red Wool
+            },
+        },
+        {
+            name: "overrides are counted as overrides",
+            table: "0.30",
+            blocks: []int8{1, 1, 2},
+            overrides: map[int8]int8{1: 20},
+            mapped: []int8{20, 20, 2},
+            remap: blockRemap{
+                {1, TableBlock{20, 0}, true}: 2,
+            },
+        },
+        {
+            name: "overrides the table doesn't have fall back",
+            table: "0.0.13a",
+            blocks: []int8{1},
+            overrides: map[int8]int8{1: 20},
+            mapped: []int8{0},
+            remap: blockRemap{
+                {1, TableBlock{0, 0}, true}: 1, // Glass -> Air
+            },
+        },
+    }
+
+    for _, test := range tests {
+        t.Run(test.name, func(t *testing.T) {
+            table, ok := BlockTableByName(test.table)
+            if !ok {
+                t.Fatalf("no %s block table", test.table)
+            }
+
+            mapped, data, remap := table.exportBlocks(test.blocks, nil, test.overrides)
+            if !reflect.DeepEqual(mapped, test.mapped) {
+                t.Errorf("blocks are %v, expected %v", mapped, test.mapped)
+            }
+            if !reflect.DeepEqual(data, test.data) {
+                t.Errorf("data is %v, expected %v", data, test.data)
+            }
+            if !reflect.DeepEqual(remap, test.remap) {
+                t.Errorf("remap is %v, expected %v", remap, test.remap)
+            }
+        })
+    }
+}
+
+func TestMapBlocksStrict(t *testing.T) {
+    format, _ := FormatByName("classic_v1")
+    level := new(Level).InitWithDefaults()
+    level.Width, level.Length, level.Height = 2, 1, 1
+    level.Blocks = []int8{1, 66}
+
+    options := *new(Options).InitWithDefaults()
+    mapped, err := format.MapBlocks(level, options)
+    if err != nil {
+        t.Fatalf("mapping without strict blocks failed: %v", err)
+    }
+    if mapped.Blocks[1] != 1 {
+        t.Errorf("block without a counterpart is %d, expected stone", mapped.Blocks[1])
+    }
+
+    options.StrictBlocks = true
+    _, err = format.MapBlocks(level, options)
+    if !errors.Is(err, ErrUnmappableBlock) {
+        t.Errorf("strict mapping returned %v, expected ErrUnmappableBlock", err)
+    }
+
+    // Fallbacks still count as a counterpart
+    level.Blocks = []int8{1, 52}
+    _, err = format.MapBlocks(level, options)
+    if err != nil {
+        t.Errorf("strict mapping of a block with a fallback failed: %v", err)
+    }
+}
+
+func TestPreClassicBlocks(t *testing.T) {
+    content := make([]byte, 256*256*64)
+    content[0] = 11 // Still Lava
+    if confidence, _ := sniffPreClassic(content); confidence == 0 {
+        t.Errorf("a save with pre-classic blocks isn't recognized")
+    }
+    content[0] = 12 // Sand came in 0.0.13a
+    if confidence, _ := sniffPreClassic(content); confidence != 0 {
+        t.Errorf("a save with sand is recognized as pre-classic")
+    }
+
+    table, _ := BlockTableByName("pre_classic")
+    mapped, _, _ := table.exportBlocks([]int8{12, 17, 20, 9}, nil, nil)
+    if !reflect.DeepEqual(mapped, []int8{3, 5, 0, 9}) {
+        t.Errorf("blocks are %v, expected [3 5 0 9]", mapped)
+    }
+}
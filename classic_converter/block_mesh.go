@@ -0,0 +1,338 @@
+package classic_converter
+
+import (
+    "math"
+)
+
+// Groups faces end up in, so see-through blocks can be given their own materials or nodes
+const (
+    meshOpaque = iota
+    meshTransparent
+    meshLiquid
+)
+
+var meshGroupNames = [3]string{"opaque", "transparent", "liquid"}
+
+// Index of each block's top, side and bottom texture in classic's 16x16 tile terrain.png
+var classicBlockTextures = map[int8][3]int{
+    1: {1, 1, 1}, // Stone
+    2: {0, 3, 2}, // Grass
+    3: {2, 2, 2}, // Dirt
+    4: {16, 16, 16}, // Cobblestone
+    5: {4, 4, 4}, // Planks
+    6: {15, 15, 15}, // Sapling
+    7: {17, 17, 17}, // Bedrock
+    8: {14, 14, 14}, // Flowing Water
+    9: {14, 14, 14}, // Still Water
+    10: {30, 30, 30}, // Flowing Lava
+    11: {30, 30, 30}, // Still Lava
+    12: {18, 18, 18}, // Sand
+    13: {19, 19, 19}, // Gravel
+    14: {32, 32, 32}, // Gold Ore
+    15: {33, 33, 33}, // Iron Ore
+    16: {34, 34, 34}, // Coal Ore
+    17: {21, 20, 21}, // Log
+    18: {22, 22, 22}, // Leaves
+    19: {48, 48, 48}, // Sponge
+    20: {49, 49, 49}, // Glass
+    37: {13, 13, 13}, // Dandelion
+    38: {12, 12, 12}, // Rose
+    39: {29, 29, 29}, // Brown Mushroom
+    40: {28, 28, 28}, // Red Mushroom
+    41: {24, 40, 56}, // Gold Block
+    42: {23, 39, 55}, // Iron Block
+    43: {6, 5, 6}, // Double Slab
+    44: {6, 5, 6}, // Slab
+    45: {7, 7, 7}, // Bricks
+    46: {9, 8, 10}, // TNT
+    47: {4, 35, 4}, // Bookshelf
+    48: {36, 36, 36}, // Mossy Cobblestone
+    49: {37, 37, 37}, // Obsidian
+}
+
+// meshFace is a textured quad, positions go counter clockwise when looking at the front of the face
+type meshFace struct {
+    Block int8
+    Positions [4][3]float32
+    UVs [4][2]float32
+    Normal [3]float32
+}
+
+// Returns the terrain.png tile of the given block face, cloth has a row of its own
+func blockTexture(block int8, axis int, sign int) int {
+    if block >= 21 && block <= 36 {
+        return 64 + int(block - 21)
+    }
+
+    textures := classicBlockTextures[block]
+    switch {
+    case axis != 1:
+        return textures[1]
+    case sign > 0:
+        return textures[0]
+    default:
+        return textures[2]
+    }
+}
+
+func blockMeshGroup(block int8) int {
+    switch block {
+    case 8, 9, 10, 11:
+        return meshLiquid
+    case 6, 18, 20, 37, 38, 39, 40:
+        return meshTransparent
+    default:
+        return meshOpaque
+    }
+}
+
+// Sprites are drawn as two crossed quads instead of a cube
+func isSprite(block int8) bool {
+    return block == 6 || (block >= 37 && block <= 40)
+}
+
+// Blocks that completely hide the face of the block next to them
+func isOccluding(block int8) bool {
+    switch block {
+    case 0, 6, 8, 9, 10, 11, 18, 20, 37, 38, 39, 40, 44:
+        return false
+    default:
+        return true
+    }
+}
+
+// Faces between two blocks of the same see-through kind aren't drawn, so a pool of water is one surface
+func isFaceVisible(block int8, neighbor int8) bool {
+    if isOccluding(neighbor) {
+        return false
+    }
+    switch block {
+    case 8, 9:
+        return neighbor != 8 && neighbor != 9
+    case 10, 11:
+        return neighbor != 10 && neighbor != 11
+    case 18, 20:
+        return neighbor != block
+    }
+    return true
+}
+
+// Builds the faces of every block that can be seen, grouped into opaque, transparent and liquid faces.
+// Greedy meshing merges neighbouring faces of the same block into one bigger face, its texture gets stretched over it.
+// Every block is gone over once for each of the 6 directions and once more for slabs and sprites, step is told after every slice.
+func buildBlockMesh(blocks []int8, width int, length int, height int, greedy bool, step func(processed int64, total int64) error) ([3][]meshFace, error) {
+    size := [3]int{width, height, length}
+    blockAt := func(position [3]int) (int8, bool) {
+        for axis := 0; axis < 3; axis++ {
+            if position[axis] < 0 || position[axis] >= size[axis] {
+                return 0, false
+            }
+        }
+        return blocks[(position[1] * length + position[2]) * width + position[0]], true
+    }
+
+    var groups [3][]meshFace
+    processed := int64(0)
+    total := int64(len(blocks)) * 7
+    addFace := func(face meshFace) {
+        group := blockMeshGroup(face.Block)
+        groups[group] = append(groups[group], face)
+    }
+
+    // Full blocks are done one slice at a time for every direction, so faces in the same plane can be merged
+    for axis := 0; axis < 3; axis++ {
+        u := (axis + 1) % 3
+        v := (axis + 2) % 3
+        mask := make([]int8, size[u] * size[v])
+
+        for _, sign := range []int{-1, 1} {
+            for slice := 0; slice < size[axis]; slice++ {
+                for j := 0; j < size[v]; j++ {
+                    for i := 0; i < size[u]; i++ {
+                        var position [3]int
+                        position[axis], position[u], position[v] = slice, i, j
+
+                        block, _ := blockAt(position)
+                        mask[j * size[u] + i] = 0
+                        if block == 0 || block == 44 || isSprite(block) {
+                            continue
+                        }
+
+                        position[axis] += sign
+                        if neighbor, ok := blockAt(position); ok && !isFaceVisible(block, neighbor) {
+                            continue
+                        }
+                        mask[j * size[u] + i] = block
+                    }
+                }
+
+                for j := 0; j < size[v]; j++ {
+                    for i := 0; i < size[u]; i++ {
+                        block := mask[j * size[u] + i]
+                        if block == 0 {
+                            continue
+                        }
+
+                        faceWidth, faceHeight := 1, 1
+                        if greedy {
+                            for i + faceWidth < size[u] && mask[j * size[u] + i + faceWidth] == block {
+                                faceWidth++
+                            }
+                            for canGrow := true; canGrow && j + faceHeight < size[v]; {
+                                for k := 0; k < faceWidth; k++ {
+                                    if mask[(j + faceHeight) * size[u] + i + k] != block {
+                                        canGrow = false
+                                        break
+                                    }
+                                }
+                                if canGrow {
+                                    faceHeight++
+                                }
+                            }
+                        }
+                        for l := 0; l < faceHeight; l++ {
+                            for k := 0; k < faceWidth; k++ {
+                                mask[(j + l) * size[u] + i + k] = 0
+                            }
+                        }
+
+                        var min, max [3]float32
+                        min[axis], min[u], min[v] = float32(slice), float32(i), float32(j)
+                        max[axis], max[u], max[v] = float32(slice + 1), float32(i + faceWidth), float32(j + faceHeight)
+                        addFace(boxFace(block, axis, sign, min, max))
+                    }
+                }
+
+                processed += int64(size[u] * size[v])
+                if err := step(processed, total); err != nil {
+                    return groups, err
+                }
+            }
+        }
+    }
+
+    // Slabs and sprites are never merged
+    for y := 0; y < height; y++ {
+        for z := 0; z < length; z++ {
+            for x := 0; x < width; x++ {
+                block, _ := blockAt([3]int{x, y, z})
+                min := [3]float32{float32(x), float32(y), float32(z)}
+
+                if block == 44 {
+                    max := [3]float32{float32(x + 1), float32(y) + 0.5, float32(z + 1)}
+                    for axis := 0; axis < 3; axis++ {
+                        for _, sign := range []int{-1, 1} {
+                            // Slab tops are never covered
+                            if axis == 1 && sign > 0 {
+                                addFace(boxFace(block, axis, sign, min, max))
+                                continue
+                            }
+
+                            neighborPosition := [3]int{x, y, z}
+                            neighborPosition[axis] += sign
+                            neighbor, ok := blockAt(neighborPosition)
+                            if ok && (isOccluding(neighbor) || (neighbor == 44 && axis != 1)) {
+                                continue
+                            }
+                            addFace(boxFace(block, axis, sign, min, max))
+                        }
+                    }
+                } else if isSprite(block) {
+                    for _, face := range spriteFaces(block, min) {
+                        addFace(face)
+                    }
+                }
+            }
+        }
+
+        processed += int64(width * length)
+        if err := step(processed, total); err != nil {
+            return groups, err
+        }
+    }
+
+    return groups, nil
+}
+
+// Returns the face of the box going from min to max on the given side
+func boxFace(block int8, axis int, sign int, min [3]float32, max [3]float32) meshFace {
+    u := (axis + 1) % 3
+    v := (axis + 2) % 3
+
+    corners := [4][2]int{{0, 0}, {1, 0}, {1, 1}, {0, 1}}
+    if sign < 0 {
+        corners = [4][2]int{{0, 0}, {0, 1}, {1, 1}, {1, 0}}
+    }
+
+    var positions [4][3]float32
+    for i, corner := range corners {
+        positions[i][axis] = ternary(sign > 0, max[axis], min[axis])
+        positions[i][u] = ternary(corner[0] == 0, min[u], max[u])
+        positions[i][v] = ternary(corner[1] == 0, min[v], max[v])
+    }
+
+    // Textures go along x or z horizontally and along y or z vertically
+    textureS, textureT := 0, 1
+    switch axis {
+    case 0:
+        textureS = 2
+    case 1:
+        textureT = 2
+    }
+
+    // Faces smaller than a block show part of the tile, bigger ones stretch it
+    var locals [4][2]float32
+    for i, position := range positions {
+        for k, textureAxis := range []int{textureS, textureT} {
+            extent := max[textureAxis] - min[textureAxis]
+            locals[i][k] = (position[textureAxis] - min[textureAxis]) / extent * clamp(extent, 0, 1)
+        }
+    }
+
+    return newMeshFace(block, positions, locals, blockTexture(block, axis, sign))
+}
+
+// Sprites are two diagonal quads, each one drawn from both sides
+func spriteFaces(block int8, min [3]float32) []meshFace {
+    x, y, z := min[0], min[1], min[2]
+    locals := [4][2]float32{{0, 0}, {1, 0}, {1, 1}, {0, 1}}
+    backLocals := [4][2]float32{{1, 0}, {0, 0}, {0, 1}, {1, 1}}
+    texture := blockTexture(block, 0, 1)
+
+    diagonals := [2][4][3]float32{
+        {{x, y, z}, {x + 1, y, z + 1}, {x + 1, y + 1, z + 1}, {x, y + 1, z}},
+        {{x + 1, y, z}, {x, y, z + 1}, {x, y + 1, z + 1}, {x + 1, y + 1, z}},
+    }
+
+    faces := []meshFace{}
+    for _, diagonal := range diagonals {
+        back := [4][3]float32{diagonal[1], diagonal[0], diagonal[3], diagonal[2]}
+        faces = append(faces, newMeshFace(block, diagonal, locals, texture), newMeshFace(block, back, backLocals, texture))
+    }
+    return faces
+}
+
+// Works out the normal and the terrain.png UVs of a face, locals go from 0 to 1 across the tile from the bottom left
+func newMeshFace(block int8, positions [4][3]float32, locals [4][2]float32, texture int) meshFace {
+    face := meshFace{
+        Block: block,
+        Positions: positions,
+    }
+
+    tileX := float32(texture % 16)
+    tileY := float32(texture / 16)
+    for i, local := range locals {
+        face.UVs[i] = [2]float32{(tileX + local[0]) / 16, 1 - (tileY + 1 - local[1]) / 16}
+    }
+
+    // Cross product of two edges, every face is a flat quad
+    a := [3]float32{positions[1][0] - positions[0][0], positions[1][1] - positions[0][1], positions[1][2] - positions[0][2]}
+    b := [3]float32{positions[3][0] - positions[0][0], positions[3][1] - positions[0][1], positions[3][2] - positions[0][2]}
+    normal := [3]float32{a[1] * b[2] - a[2] * b[1], a[2] * b[0] - a[0] * b[2], a[0] * b[1] - a[1] * b[0]}
+    length := float32(math.Sqrt(float64(normal[0] * normal[0] + normal[1] * normal[1] + normal[2] * normal[2])))
+    for i := range normal {
+        face.Normal[i] = normal[i] / length
+    }
+
+    return face
+}
@@ -0,0 +1,172 @@
+package classic_converter
+
+import (
+    "fmt"
+    "sort"
+)
+
+// Highest block ID each classic version knows about
+var classicVersionMaxBlock = map[string]int8{
+    "0.0.13a": 13,
+    "0.0.14a": 18,
+    "0.0.15a": 18,
+    "0.0.16a": 18,
+    "0.0.17a": 18,
+    "0.0.18a": 18,
+    "0.0.19a": 36,
+    "0.0.20a": 41,
+    "0.0.21a": 41,
+    "0.0.22a": 41,
+    "0.0.23a": 41,
+    "0.24": 41,
+    "0.25": 41,
+    "0.26": 49,
+    "0.27": 49,
+    "0.28": 49,
+    "0.29": 49,
+    "0.30": 49,
+}
+
+// Older looking block to use for each block missing from a classic version, followed until a known block is found
+var classicBlockFallback = map[int8]int8{
+    12: 3, // Sand -> Dirt
+    13: 3, // Gravel -> Dirt
+    14: 1, // Gold Ore -> Stone
+    15: 1, // Iron Ore -> Stone
+    16: 1, // Coal Ore -> Stone
+    17: 5, // Log -> Planks
+    18: 0, // Leaves -> Air
+    19: 12, // Sponge -> Sand
+    20: 0, // Glass -> Air
+    37: 6, // Dandelion -> Sapling
+    38: 6, // Rose -> Sapling
+    39: 6, // Brown Mushroom -> Sapling
+    40: 6, // Red Mushroom -> Sapling
+    41: 14, // Gold Block -> Gold Ore
+    42: 15, // Iron Block -> Iron Ore
+    43: 1, // Double Slab -> Stone
+    44: 1, // Slab -> Stone
+    45: 4, // Bricks -> Cobblestone
+    46: 45, // TNT -> Bricks
+    47: 5, // Bookshelf -> Planks
+    48: 4, // Mossy Cobblestone -> Cobblestone
+    49: 1, // Obsidian -> Stone
+    // CPE CustomBlocks
+    50: 44, // Cobblestone Slab -> Slab
+    51: 0, // Rope -> Air
+    52: 12, // Sandstone -> Sand
+    53: 0, // Snow -> Air
+    54: 0, // Fire -> Air
+    55: 33, // Light Pink Cloth -> Pink Cloth
+    56: 25, // Forest Green Cloth -> Green Cloth
+    57: 22, // Brown Cloth -> Orange Cloth
+    58: 28, // Deep Blue Cloth -> Blue Cloth
+    59: 27, // Turquoise Cloth -> Cyan Cloth
+    60: 20, // Ice -> Glass
+    61: 1, // Ceramic Tile -> Stone
+    62: 11, // Magma -> Still Lava
+    63: 36, // Pillar -> White Cloth
+    64: 5, // Crate -> Planks
+    65: 1, // Stone Brick -> Stone
+}
+
+// Returns every classic version that has a known block set, oldest first
+func ClassicVersions() []string {
+    versions := make([]string, 0, len(classicVersionMaxBlock))
+    for version := range classicVersionMaxBlock {
+        versions = append(versions, version)
+    }
+    sort.Slice(versions, func(i, j int) bool {
+        // "0.0.x" versions came before "0.2x" versions
+        if len(versions[i]) != len(versions[j]) {
+            return len(versions[i]) > len(versions[j])
+        }
+        return versions[i] < versions[j]
+    })
+    return versions
+}
+
+// Returns the highest block ID the given classic version knows about
+func ClassicMaxBlock(version string) (int8, bool) {
+    maxBlock, ok := classicVersionMaxBlock[version]
+    return maxBlock, ok
+}
+
+// Names used for block materials and legends
+var classicBlockNames = map[int8]string{
+    0: "air",
+    1: "stone",
+    2: "grass",
+    3: "dirt",
+    4: "cobblestone",
+    5: "planks",
+    6: "sapling",
+    7: "bedrock",
+    8: "flowing_water",
+    9: "water",
+    10: "flowing_lava",
+    11: "lava",
+    12: "sand",
+    13: "gravel",
+    14: "gold_ore",
+    15: "iron_ore",
+    16: "coal_ore",
+    17: "log",
+    18: "leaves",
+    19: "sponge",
+    20: "glass",
+    21: "red_cloth",
+    22: "orange_cloth",
+    23: "yellow_cloth",
+    24: "lime_cloth",
+    25: "green_cloth",
+    26: "aqua_green_cloth",
+    27: "cyan_cloth",
+    28: "blue_cloth",
+    29: "purple_cloth",
+    30: "indigo_cloth",
+    31: "violet_cloth",
+    32: "magenta_cloth",
+    33: "pink_cloth",
+    34: "black_cloth",
+    35: "gray_cloth",
+    36: "white_cloth",
+    37: "dandelion",
+    38: "rose",
+    39: "brown_mushroom",
+    40: "red_mushroom",
+    41: "gold_block",
+    42: "iron_block",
+    43: "double_slab",
+    44: "slab",
+    45: "bricks",
+    46: "tnt",
+    47: "bookshelf",
+    48: "mossy_cobblestone",
+    49: "obsidian",
+    // CPE CustomBlocks
+    50: "cobblestone_slab",
+    51: "rope",
+    52: "sandstone",
+    53: "snow",
+    54: "fire",
+    55: "light_pink_cloth",
+    56: "forest_green_cloth",
+    57: "brown_cloth",
+    58: "deep_blue_cloth",
+    59: "turquoise_cloth",
+    60: "ice",
+    61: "ceramic_tile",
+    62: "magma",
+    63: "pillar",
+    64: "crate",
+    65: "stone_brick",
+}
+
+// Returns the name of the given block, unknown blocks are named after their ID
+func ClassicBlockName(block int8) string {
+    if name, ok := classicBlockNames[block]; ok {
+        return name
+    }
+    return fmt.Sprintf("block_%d", block)
+}
@@ -0,0 +1,82 @@
+package classic_converter
+
+import (
+    "bytes"
+    "compress/gzip"
+    "encoding/binary"
+    "io"
+    "time"
+)
+
+// ClassicV1Level is the version 1 classic level format read by Classic 0.0.13a - 0.0.23a.
+type ClassicV1Level struct {
+    Name string
+    Creator string
+    CreateTime int64
+    Width int16
+    Length int16
+    Height int16
+    Blocks []int8
+}
+
+func (classic_level *ClassicV1Level) InitWithDefaults() *ClassicV1Level {
+    classic_level.Name = "A Nice World"
+    classic_level.Creator = ""
+    classic_level.CreateTime = time.Now().UnixMilli()
+    classic_level.Width = 256
+    classic_level.Length = 256
+    classic_level.Height = 64
+    classic_level.Blocks = make([]int8, 256*256*64)
+
+    return classic_level
+}
+
+func (classic_level *ClassicV1Level) FromLevel(level *Level) {
+    classic_level.Name = level.Name
+    classic_level.Creator = level.Author
+    classic_level.CreateTime = level.CreatedOn
+    classic_level.Width = level.Width
+    classic_level.Length = level.Length
+    classic_level.Height = level.Height
+    classic_level.Blocks = level.Blocks
+}
+
+func (classic_level *ClassicV1Level) WriteTo(w io.Writer) (int64, error) {
+    blocks := classic_level.Blocks
+
+    buffer := new(bytes.Buffer)
+    gzWriter := gzip.NewWriter(buffer)
+
+    // Strings are written the same way as Java's DataOutputStream.writeUTF
+    fields := []any{
+        int32(0x271bb788), // Magic
+        uint8(1), // Version
+        uint16(len(classic_level.Name)),
+        []byte(classic_level.Name),
+        uint16(len(classic_level.Creator)),
+        []byte(classic_level.Creator),
+        classic_level.CreateTime,
+        classic_level.Width,
+        classic_level.Length,
+        classic_level.Height,
+        blocks,
+    }
+    for _, value := range fields {
+        err := binary.Write(gzWriter, binary.BigEndian, value)
+        if err != nil {
+            return 0, err
+        }
+    }
+
+    err := gzWriter.Close()
+    if err != nil {
+        return 0, err
+    }
+
+    n, err := w.Write(buffer.Bytes())
+    return int64(n), err
+}
+
+func (classic_level *ClassicV1Level) WriteToFile(filename string) error {
+    return writeFile(filename, classic_level)
+}
@@ -0,0 +1,552 @@
+package classic_converter
+
+import (
+    "bytes"
+    "compress/gzip"
+    "encoding/binary"
+    "io"
+    "time"
+
+    "github.com/BJTMastermind/Go-MC-Classic-Parser"
+)
+
+// Classic's own classes all use a serialVersionUID of 0
+var (
+    classicAABBClass = newJavaClass("com.mojang.minecraft.phys.AABB", 0, javaSCSerializable, nil, []javaField{
+        {Name: "epsilon", TypeCode: 'F'},
+        {Name: "x0", TypeCode: 'F'},
+        {Name: "y0", TypeCode: 'F'},
+        {Name: "z0", TypeCode: 'F'},
+        {Name: "x1", TypeCode: 'F'},
+        {Name: "y1", TypeCode: 'F'},
+        {Name: "z1", TypeCode: 'F'},
+    })
+    classicEntityClass = newJavaClass("com.mojang.minecraft.Entity", 0, javaSCSerializable, nil, []javaField{
+        {Name: "bb", TypeCode: 'L', Signature: "Lcom/mojang/minecraft/phys/AABB;"},
+        {Name: "bbHeight", TypeCode: 'F'},
+        {Name: "bbWidth", TypeCode: 'F'},
+        {Name: "blockMap", TypeCode: 'L', Signature: "Lcom/mojang/minecraft/level/BlockMap;"},
+        {Name: "collision", TypeCode: 'Z'},
+        {Name: "fallDistance", TypeCode: 'F'},
+        {Name: "footSize", TypeCode: 'F'},
+        {Name: "heightOffset", TypeCode: 'F'},
+        {Name: "horizontalCollision", TypeCode: 'Z'},
+        {Name: "hovered", TypeCode: 'Z'},
+        {Name: "level", TypeCode: 'L', Signature: "Lcom/mojang/minecraft/level/Level;"},
+        {Name: "makeStepSound", TypeCode: 'Z'},
+        {Name: "noPhysics", TypeCode: 'Z'},
+        {Name: "onGround", TypeCode: 'Z'},
+        {Name: "pushthrough", TypeCode: 'F'},
+        {Name: "removed", TypeCode: 'Z'},
+        {Name: "slide", TypeCode: 'Z'},
+        {Name: "textureId", TypeCode: 'I'},
+        {Name: "walkDist", TypeCode: 'F'},
+        {Name: "walkDistO", TypeCode: 'F'},
+        {Name: "x", TypeCode: 'F'},
+        {Name: "xOld", TypeCode: 'F'},
+        {Name: "xRot", TypeCode: 'F'},
+        {Name: "xRotO", TypeCode: 'F'},
+        {Name: "xd", TypeCode: 'F'},
+        {Name: "xo", TypeCode: 'F'},
+        {Name: "y", TypeCode: 'F'},
+        {Name: "yOld", TypeCode: 'F'},
+        {Name: "yRot", TypeCode: 'F'},
+        {Name: "yRotO", TypeCode: 'F'},
+        {Name: "ySlideOffset", TypeCode: 'F'},
+        {Name: "yd", TypeCode: 'F'},
+        {Name: "yo", TypeCode: 'F'},
+        {Name: "z", TypeCode: 'F'},
+        {Name: "zOld", TypeCode: 'F'},
+        {Name: "zd", TypeCode: 'F'},
+        {Name: "zo", TypeCode: 'F'},
+    })
+    classicMobClass = newJavaClass("com.mojang.minecraft.mob.Mob", 0, javaSCSerializable, classicEntityClass, []javaField{
+        {Name: "airSupply", TypeCode: 'I'},
+        {Name: "allowAlpha", TypeCode: 'Z'},
+        {Name: "animStep", TypeCode: 'F'},
+        {Name: "animStepO", TypeCode: 'F'},
+        {Name: "attackTime", TypeCode: 'I'},
+        {Name: "bobStrength", TypeCode: 'F'},
+        {Name: "dead", TypeCode: 'Z'},
+        {Name: "deathScore", TypeCode: 'I'},
+        {Name: "deathTime", TypeCode: 'I'},
+        {Name: "health", TypeCode: 'I'},
+        {Name: "hurtDir", TypeCode: 'F'},
+        {Name: "hurtDuration", TypeCode: 'I'},
+        {Name: "hurtTime", TypeCode: 'I'},
+        {Name: "invulnerableDuration", TypeCode: 'I'},
+        {Name: "invulnerableTime", TypeCode: 'I'},
+        {Name: "lastHealth", TypeCode: 'I'},
+        {Name: "modelName", TypeCode: 'L', Signature: "Ljava/lang/String;"},
+        {Name: "oRun", TypeCode: 'F'},
+        {Name: "oTilt", TypeCode: 'F'},
+        {Name: "renderOffset", TypeCode: 'F'},
+        {Name: "rot", TypeCode: 'F'},
+        {Name: "rotA", TypeCode: 'F'},
+        {Name: "rotOffs", TypeCode: 'F'},
+        {Name: "run", TypeCode: 'F'},
+        {Name: "speed", TypeCode: 'F'},
+        {Name: "textureName", TypeCode: 'L', Signature: "Ljava/lang/String;"},
+        {Name: "tickCount", TypeCode: 'I'},
+        {Name: "tilt", TypeCode: 'F'},
+        {Name: "timeOffs", TypeCode: 'F'},
+        {Name: "yBodyRot", TypeCode: 'F'},
+        {Name: "yBodyRotO", TypeCode: 'F'},
+        {Name: "ai", TypeCode: 'L', Signature: "Lcom/mojang/minecraft/mob/ai/AI;"},
+    })
+    classicHumanoidMobClass = newJavaClass("com.mojang.minecraft.mob.HumanoidMob", 0, javaSCSerializable, classicMobClass, nil)
+    classicQuadrupedMobClass = newJavaClass("com.mojang.minecraft.mob.QuadrupedMob", 0, javaSCSerializable, classicMobClass, nil)
+    classicZombieClass = newJavaClass("com.mojang.minecraft.mob.Zombie", 0, javaSCSerializable, classicHumanoidMobClass, nil)
+    classicSkeletonClass = newJavaClass("com.mojang.minecraft.mob.Skeleton", 0, javaSCSerializable, classicZombieClass, nil)
+    classicCreeperClass = newJavaClass("com.mojang.minecraft.mob.Creeper", 0, javaSCSerializable, classicMobClass, nil)
+    classicSpiderClass = newJavaClass("com.mojang.minecraft.mob.Spider", 0, javaSCSerializable, classicQuadrupedMobClass, nil)
+    classicPigClass = newJavaClass("com.mojang.minecraft.mob.Pig", 0, javaSCSerializable, classicQuadrupedMobClass, nil)
+    classicSheepClass = newJavaClass("com.mojang.minecraft.mob.Sheep", 0, javaSCSerializable, classicQuadrupedMobClass, []javaField{
+        {Name: "hasHair", TypeCode: 'Z'},
+    })
+    // Mobs tick their AI every tick, so every mob needs one
+    classicAIClass = newJavaClass("com.mojang.minecraft.mob.ai.AI", 0, javaSCSerializable, nil, []javaField{
+        {Name: "defaultLookAngle", TypeCode: 'I'},
+    })
+    classicBasicAIClass = newJavaClass("com.mojang.minecraft.mob.ai.BasicAI", 0, javaSCSerializable, classicAIClass, []javaField{
+        {Name: "attackDelay", TypeCode: 'I'},
+        {Name: "attackTarget", TypeCode: 'L', Signature: "Lcom/mojang/minecraft/Entity;"},
+        {Name: "jumping", TypeCode: 'Z'},
+        {Name: "level", TypeCode: 'L', Signature: "Lcom/mojang/minecraft/level/Level;"},
+        {Name: "mob", TypeCode: 'L', Signature: "Lcom/mojang/minecraft/mob/Mob;"},
+        {Name: "noActionTime", TypeCode: 'I'},
+        {Name: "random", TypeCode: 'L', Signature: "Ljava/util/Random;"},
+        {Name: "runSpeed", TypeCode: 'F'},
+        {Name: "running", TypeCode: 'Z'},
+        {Name: "xxa", TypeCode: 'F'},
+        {Name: "yRotA", TypeCode: 'F'},
+        {Name: "yya", TypeCode: 'F'},
+    })
+    classicBasicAttackAIClass = newJavaClass("com.mojang.minecraft.mob.ai.BasicAttackAI", 0, javaSCSerializable, classicBasicAIClass, []javaField{
+        {Name: "damage", TypeCode: 'I'},
+    })
+    classicInventoryClass = newJavaClass("com.mojang.minecraft.player.Inventory", 0, javaSCSerializable, nil, []javaField{
+        {Name: "count", TypeCode: '[', Signature: "[I"},
+        {Name: "popTime", TypeCode: '[', Signature: "[I"},
+        {Name: "selected", TypeCode: 'I'},
+        {Name: "slots", TypeCode: '[', Signature: "[I"},
+    })
+    classicPlayerClass = newJavaClass("com.mojang.minecraft.player.Player", 0, javaSCSerializable, classicMobClass, []javaField{
+        {Name: "arrows", TypeCode: 'I'},
+        {Name: "bob", TypeCode: 'F'},
+        {Name: "inventory", TypeCode: 'L', Signature: "Lcom/mojang/minecraft/player/Inventory;"},
+        {Name: "oBob", TypeCode: 'F'},
+        {Name: "score", TypeCode: 'I'},
+        {Name: "userType", TypeCode: 'B'},
+    })
+    classicBlockMapClass = newJavaClass("com.mojang.minecraft.level.BlockMap", 0, javaSCSerializable, nil, []javaField{
+        {Name: "all", TypeCode: 'L', Signature: "Ljava/util/List;"},
+        {Name: "depth", TypeCode: 'I'},
+        {Name: "entityGrid", TypeCode: '[', Signature: "[Ljava/util/List;"},
+        {Name: "height", TypeCode: 'I'},
+        {Name: "slot", TypeCode: 'L', Signature: "Lcom/mojang/minecraft/level/BlockMap$Slot;"},
+        {Name: "slot2", TypeCode: 'L', Signature: "Lcom/mojang/minecraft/level/BlockMap$Slot;"},
+        {Name: "tmp", TypeCode: 'L', Signature: "Ljava/util/List;"},
+        {Name: "width", TypeCode: 'I'},
+    })
+    classicBlockMapSlotClass = newJavaClass("com.mojang.minecraft.level.BlockMap$Slot", 0, javaSCSerializable, nil, []javaField{
+        {Name: "this$0", TypeCode: 'L', Signature: "Lcom/mojang/minecraft/level/BlockMap;"},
+        {Name: "xSlot", TypeCode: 'I'},
+        {Name: "ySlot", TypeCode: 'I'},
+        {Name: "zSlot", TypeCode: 'I'},
+    })
+    classicListArrayClass = &javaClass{Name: "[Ljava.util.List;", Flags: javaSCSerializable}
+    classicLevelClass = newJavaClass("com.mojang.minecraft.level.Level", 0, javaSCSerializable, nil, []javaField{
+        {Name: "blockMap", TypeCode: 'L', Signature: "Lcom/mojang/minecraft/level/BlockMap;"},
+        {Name: "blocks", TypeCode: '[', Signature: "[B"},
+        {Name: "cloudColor", TypeCode: 'I'},
+        {Name: "createTime", TypeCode: 'J'},
+        {Name: "creativeMode", TypeCode: 'Z'},
+        {Name: "creator", TypeCode: 'L', Signature: "Ljava/lang/String;"},
+        {Name: "depth", TypeCode: 'I'},
+        {Name: "fogColor", TypeCode: 'I'},
+        {Name: "growTrees", TypeCode: 'Z'},
+        {Name: "height", TypeCode: 'I'},
+        {Name: "name", TypeCode: 'L', Signature: "Ljava/lang/String;"},
+        {Name: "player", TypeCode: 'L', Signature: "Lcom/mojang/minecraft/Entity;"},
+        {Name: "rotSpawn", TypeCode: 'F'},
+        {Name: "skyColor", TypeCode: 'I'},
+        {Name: "waterLevel", TypeCode: 'I'},
+        {Name: "width", TypeCode: 'I'},
+        {Name: "xSpawn", TypeCode: 'I'},
+        {Name: "ySpawn", TypeCode: 'I'},
+        {Name: "zSpawn", TypeCode: 'I'},
+    })
+)
+
+// ClassicV2Level is the version 2 classic level format read by Classic 0.0.24 - 0.30,
+// a Java serialized com.mojang.minecraft.level.Level.
+type ClassicV2Level struct {
+    Name string
+    Creator string
+    CreateTime int64
+    Width int16
+    Length int16
+    Height int16
+    Spawn [3]int16
+    RotSpawn float32
+    WaterLevel int32
+    SkyColor int32
+    FogColor int32
+    CloudColor int32
+    CreativeMode bool
+    GrowTrees bool
+    Blocks []int8
+    Entities []mc_classic_parser.ClassicEntity
+    Player *mc_classic_parser.ClassicPlayer
+}
+
+func (classic_level *ClassicV2Level) InitWithDefaults() *ClassicV2Level {
+    classic_level.Name = "A Nice World"
+    classic_level.Creator = ""
+    classic_level.CreateTime = time.Now().UnixMilli()
+    classic_level.Width = 256
+    classic_level.Length = 256
+    classic_level.Height = 64
+    classic_level.Spawn = [3]int16{128, 33, 128}
+    classic_level.RotSpawn = 0
+    classic_level.WaterLevel = 32
+    classic_level.SkyColor = 10079487
+    classic_level.FogColor = 16777215
+    classic_level.CloudColor = 16777215
+    classic_level.CreativeMode = false
+    classic_level.GrowTrees = false
+    classic_level.Blocks = make([]int8, 256*256*64)
+    classic_level.Entities = []mc_classic_parser.ClassicEntity{}
+    classic_level.Player = nil
+
+    return classic_level
+}
+
+func (classic_level *ClassicV2Level) FromLevel(level *Level) {
+    classic_level.Name = level.Name
+    classic_level.Creator = level.Author
+    classic_level.CreateTime = level.CreatedOn
+    classic_level.Width = level.Width
+    classic_level.Length = level.Length
+    classic_level.Height = level.Height
+    classic_level.Blocks = level.Blocks
+    classic_level.WaterLevel = level.WaterLevel
+    classic_level.CreativeMode = level.CreativeMode
+    classic_level.GrowTrees = level.GrowTrees
+    classic_level.Entities = level.Entities
+    classic_level.Player = level.Player
+
+    // Classic has no client default colors, so those keep classic's own
+    classic_level.SkyColor = ternary(level.SkyColor >= 0, level.SkyColor, classic_level.SkyColor)
+    classic_level.FogColor = ternary(level.FogColor >= 0, level.FogColor, classic_level.FogColor)
+    classic_level.CloudColor = ternary(level.CloudColor >= 0, level.CloudColor, classic_level.CloudColor)
+
+    if level.HasSpawn {
+        classic_level.Spawn = level.Spawn
+        classic_level.RotSpawn = level.SpawnYaw
+    } else {
+        classic_level.FindSpawn()
+    }
+}
+
+func (classic_level *ClassicV2Level) FindSpawn() {
+    classic_level.Spawn = findSafeSpawn(classic_level.Blocks, classic_level.Width, classic_level.Length, classic_level.Height)
+}
+
+func (classic_level *ClassicV2Level) WriteTo(w io.Writer) (int64, error) {
+    blocks := classic_level.Blocks
+
+    // Classic calls the vertical axis depth and the z axis height
+    level := &javaObject{
+        Class: classicLevelClass,
+        Values: map[string]any{
+            "blocks": blocks,
+            "cloudColor": classic_level.CloudColor,
+            "createTime": classic_level.CreateTime,
+            "creativeMode": classic_level.CreativeMode,
+            "creator": classic_level.Creator,
+            "depth": int32(classic_level.Height),
+            "fogColor": classic_level.FogColor,
+            "growTrees": classic_level.GrowTrees,
+            "height": int32(classic_level.Length),
+            "name": classic_level.Name,
+            "rotSpawn": classic_level.RotSpawn,
+            "skyColor": classic_level.SkyColor,
+            "waterLevel": classic_level.WaterLevel,
+            "width": int32(classic_level.Width),
+            "xSpawn": int32(classic_level.Spawn[0]),
+            "ySpawn": int32(classic_level.Spawn[1]),
+            "zSpawn": int32(classic_level.Spawn[2]),
+        },
+    }
+
+    blockMap := classic_level.newBlockMap()
+    level.Values["blockMap"] = blockMap
+
+    for i, entity := range classic_level.Entities {
+        if entity.TextureName == "/char.png" {
+            continue
+        }
+
+        object := classicEntity2Object(entity, textureName2Class(entity.TextureName))
+        object.Values["level"] = level
+        object.Values["blockMap"] = blockMap
+        object.Values["ai"] = classicMobAI(object, level, int64(i))
+        classic_level.insertIntoBlockMap(blockMap, object, entity.X, entity.Y, entity.Z)
+    }
+
+    if classic_level.Player != nil {
+        player := classicPlayer2Object(*classic_level.Player)
+        player.Values["level"] = level
+        player.Values["blockMap"] = blockMap
+        classic_level.insertIntoBlockMap(blockMap, player, classic_level.Player.X, classic_level.Player.Y, classic_level.Player.Z)
+        level.Values["player"] = player
+    }
+
+    writer := newJavaObjectWriter()
+    writer.WriteObject(level)
+
+    buffer := new(bytes.Buffer)
+    gzWriter := gzip.NewWriter(buffer)
+
+    fields := []any{
+        int32(0x271bb788), // Magic
+        uint8(2), // Version
+        writer.Bytes(),
+    }
+    for _, value := range fields {
+        err := binary.Write(gzWriter, binary.BigEndian, value)
+        if err != nil {
+            return 0, err
+        }
+    }
+
+    err := gzWriter.Close()
+    if err != nil {
+        return 0, err
+    }
+
+    n, err := w.Write(buffer.Bytes())
+    return int64(n), err
+}
+
+func (classic_level *ClassicV2Level) WriteToFile(filename string) error {
+    return writeFile(filename, classic_level)
+}
+
+// Size of the entity grid, BlockMap splits the level into 16x16x16 cells
+func (classic_level *ClassicV2Level) blockMapSize() (int32, int32, int32) {
+    width := clamp(int32(classic_level.Width) / 16, 1, int32(classic_level.Width))
+    depth := clamp(int32(classic_level.Height) / 16, 1, int32(classic_level.Height))
+    height := clamp(int32(classic_level.Length) / 16, 1, int32(classic_level.Length))
+    return width, depth, height
+}
+
+func (classic_level *ClassicV2Level) newBlockMap() *javaObject {
+    width, depth, height := classic_level.blockMapSize()
+
+    grid := make([]any, width * depth * height)
+    for i := range grid {
+        grid[i] = newJavaArrayList([]any{})
+    }
+
+    blockMap := &javaObject{
+        Class: classicBlockMapClass,
+        Values: map[string]any{
+            "all": newJavaArrayList([]any{}),
+            "depth": depth,
+            "entityGrid": &javaArray{Class: classicListArrayClass, Values: grid},
+            "height": height,
+            "tmp": newJavaArrayList([]any{}),
+            "width": width,
+        },
+    }
+    for _, name := range []string{"slot", "slot2"} {
+        blockMap.Values[name] = &javaObject{
+            Class: classicBlockMapSlotClass,
+            Values: map[string]any{
+                "this$0": blockMap,
+            },
+        }
+    }
+
+    return blockMap
+}
+
+// Adds the entity to BlockMap.all and to the entity grid cell it is standing in
+func (classic_level *ClassicV2Level) insertIntoBlockMap(blockMap *javaObject, entity *javaObject, x float32, y float32, z float32) {
+    width, depth, height := classic_level.blockMapSize()
+
+    xSlot := clamp(int32(x / 16), 0, width - 1)
+    ySlot := clamp(int32(y / 16), 0, depth - 1)
+    zSlot := clamp(int32(z / 16), 0, height - 1)
+
+    all := blockMap.Values["all"].(*javaObject)
+    all.Annotations = append(all.Annotations, entity)
+    all.Values["size"] = int32(len(all.Annotations))
+    binary.BigEndian.PutUint32(all.BlockData, uint32(len(all.Annotations)))
+
+    cell := blockMap.Values["entityGrid"].(*javaArray).Values[(zSlot * depth + ySlot) * width + xSlot].(*javaObject)
+    cell.Annotations = append(cell.Annotations, entity)
+    cell.Values["size"] = int32(len(cell.Annotations))
+    binary.BigEndian.PutUint32(cell.BlockData, uint32(len(cell.Annotations)))
+}
+
+func textureName2Class(textureName string) *javaClass {
+    switch textureName {
+        case "/mob/zombie.png":
+            return classicZombieClass
+        case "/mob/skeleton.png":
+            return classicSkeletonClass
+        case "/mob/creeper.png":
+            return classicCreeperClass
+        case "/mob/spider.png":
+            return classicSpiderClass
+        case "/mob/pig.png":
+            return classicPigClass
+        case "/mob/sheep.png":
+            return classicSheepClass
+        default:
+            return classicZombieClass
+    }
+}
+
+// Hostile mobs attack with BasicAttackAI and the others wander around with BasicAI, both start out the way 0.30 makes them
+func classicMobAI(mob *javaObject, level *javaObject, seed int64) *javaObject {
+    class := classicBasicAIClass
+    switch mob.Class {
+    case classicZombieClass, classicSkeletonClass, classicCreeperClass, classicSpiderClass:
+        class = classicBasicAttackAIClass
+    }
+
+    return &javaObject{
+        Class: class,
+        Values: map[string]any{
+            "damage": int32(6),
+            "level": level,
+            "mob": mob,
+            "random": newJavaRandom(seed),
+            "runSpeed": float32(0.7),
+        },
+    }
+}
+
+func classicBB2Object(bb map[string]float32) *javaObject {
+    values := map[string]any{}
+    for key, value := range bb {
+        values[key] = value
+    }
+    return &javaObject{
+        Class: classicAABBClass,
+        Values: values,
+    }
+}
+
+func classicEntity2Object(entity mc_classic_parser.ClassicEntity, class *javaClass) *javaObject {
+    return &javaObject{
+        Class: class,
+        Values: map[string]any{
+            "bb": classicBB2Object(entity.Bb),
+            "bbHeight": entity.BbHeight,
+            "bbWidth": entity.BbWidth,
+            "collision": entity.Collision,
+            "fallDistance": entity.FallDistance,
+            "footSize": entity.FootSize,
+            "heightOffset": entity.HeightOffset,
+            "horizontalCollision": entity.HorizontalCollision,
+            "hovered": entity.Hovered,
+            "makeStepSound": entity.MakeStepSound,
+            "noPhysics": entity.NoPhysics,
+            "onGround": entity.OnGround,
+            "pushthrough": entity.Pushthrough,
+            "removed": entity.Removed,
+            "slide": entity.Slide,
+            "textureId": entity.TextureId,
+            "walkDist": entity.WalkDist,
+            "walkDistO": entity.WalkDistO,
+            "x": entity.X,
+            "xOld": entity.XOld,
+            "xRot": entity.XRot,
+            "xRotO": entity.XRotO,
+            "xd": entity.Xd,
+            "xo": entity.Xo,
+            "y": entity.Y,
+            "yOld": entity.YOld,
+            "yRot": entity.YRot,
+            "yRotO": entity.YRotO,
+            "ySlideOffset": entity.YSlideOffset,
+            "yd": entity.Yd,
+            "yo": entity.Yo,
+            "z": entity.Z,
+            "zOld": entity.ZOld,
+            "zd": entity.Zd,
+            "zo": entity.Zo,
+            // Mob
+            "airSupply": entity.AirSupply,
+            "allowAlpha": entity.AllowAlpha,
+            "animStep": entity.AnimStep,
+            "animStepO": entity.AnimStepO,
+            "attackTime": entity.AttackTime,
+            "bobStrength": entity.BobStrength,
+            "dead": entity.Dead,
+            "deathScore": entity.DeathScore,
+            "deathTime": entity.DeathTime,
+            "health": entity.Health,
+            "hurtDir": entity.HurtDir,
+            "hurtDuration": entity.HurtDuration,
+            "hurtTime": entity.HurtTime,
+            "invulnerableDuration": entity.InvulnerableDuration,
+            "invulnerableTime": entity.InvulnerableTime,
+            "lastHealth": entity.LastHealth,
+            "modelName": entity.ModelName,
+            "oRun": entity.ORun,
+            "oTilt": entity.OTilt,
+            "renderOffset": entity.RenderOffset,
+            "rot": entity.Rot,
+            "rotA": entity.RotA,
+            "rotOffs": entity.RotOffs,
+            "run": entity.Run,
+            "speed": entity.Speed,
+            "textureName": entity.TextureName,
+            "tickCount": entity.TickCount,
+            "tilt": entity.Tilt,
+            "timeOffs": entity.TimeOffs,
+            "yBodyRot": entity.YBodyRot,
+            "yBodyRotO": entity.YBodyRotO,
+            // Sheep
+            "hasHair": entity.HasHair,
+        },
+    }
+}
+
+func classicPlayer2Object(player mc_classic_parser.ClassicPlayer) *javaObject {
+    object := classicEntity2Object(player.ClassicEntity, classicPlayerClass)
+    object.Values["arrows"] = player.Arrows
+    object.Values["bob"] = player.Bob
+    object.Values["oBob"] = player.OBob
+    object.Values["score"] = player.Score
+    object.Values["userType"] = player.UserType
+
+    slots, _ := player.Inventory["slots"].([]int32)
+    count, _ := player.Inventory["count"].([]int32)
+    selected, _ := player.Inventory["selected"].(int32)
+    if slots == nil {
+        slots = []int32{-1, -1, -1, -1, -1, -1, -1, -1, -1}
+    }
+    if count == nil {
+        count = make([]int32, len(slots))
+    }
+
+    object.Values["inventory"] = &javaObject{
+        Class: classicInventoryClass,
+        Values: map[string]any{
+            "count": count,
+            "popTime": make([]int32, len(slots)),
+            "selected": selected,
+            "slots": slots,
+        },
+    }
+
+    return object
+}
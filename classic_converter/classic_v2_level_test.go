@@ -0,0 +1,144 @@
+package classic_converter
+
+import (
+    "bytes"
+    "compress/gzip"
+    "flag"
+    "io"
+    "os"
+    "path/filepath"
+    "testing"
+
+    "github.com/BJTMastermind/Go-MC-Classic-Parser"
+)
+
+var update = flag.Bool("update", false, "rewrite the golden files in testdata")
+
+// testClassicV2Level is a small fixed world with a player and two mobs, so the written stream never changes
+func testClassicV2Level() *Level {
+    level := new(Level).InitWithDefaults()
+    level.Name = "Golden"
+    level.Author = "tester"
+    level.CreatedOn = 1262304000000
+    level.Width, level.Length, level.Height = 16, 16, 16
+    level.Blocks = make([]int8, 16*16*16)
+    for i := 0; i < 16*16*8; i++ {
+        level.Blocks[i] = 3
+    }
+    level.HasSpawn = true
+    level.Spawn = [3]int16{8, 9, 8}
+    level.WaterLevel = 8
+
+    bb := map[string]float32{"epsilon": 0, "x0": 7.7, "y0": 8, "z0": 7.7, "x1": 8.3, "y1": 9.8, "z1": 8.3}
+    player := &mc_classic_parser.ClassicPlayer{
+        ClassicEntity: mc_classic_parser.ClassicEntity{Bb: bb, Health: 20, TextureName: "/char.png", X: 8, Y: 9.62, Z: 8},
+        Arrows: 20,
+        Score: 7,
+        Inventory: map[string]any{
+            "slots": []int32{1, 4, 45, 3, 5, 17, 18, 20, 44},
+            "count": []int32{1, 1, 1, 1, 1, 1, 1, 1, 1},
+            "selected": int32(2),
+        },
+    }
+    zombie := mc_classic_parser.ClassicEntity{Bb: bb, Health: 20, TextureName: "/mob/zombie.png", X: 4, Y: 9.62, Z: 4}
+    sheep := mc_classic_parser.ClassicEntity{Bb: bb, Health: 10, TextureName: "/mob/sheep.png", HasHair: true, X: 12, Y: 9.62, Z: 12}
+    level.Player = player
+    level.Entities = []mc_classic_parser.ClassicEntity{zombie, sheep, player.ClassicEntity}
+    return level
+}
+
+func writeTestClassicV2Level(t *testing.T) []byte {
+    classic_level := new(ClassicV2Level).InitWithDefaults()
+    classic_level.FromLevel(testClassicV2Level())
+
+    buffer := new(bytes.Buffer)
+    _, err := classic_level.WriteTo(buffer)
+    if err != nil {
+        t.Fatalf("writing the level failed: %v", err)
+    }
+    return buffer.Bytes()
+}
+
+func TestClassicV2LevelRoundTrip(t *testing.T) {
+    expected := testClassicV2Level()
+    level, err := ReadClassicLevel(bytes.NewReader(writeTestClassicV2Level(t)))
+    if err != nil {
+        t.Fatalf("mc_classic_parser could not read the level back: %v", err)
+    }
+
+    if level.Name != expected.Name || level.Author != expected.Author || level.CreatedOn != expected.CreatedOn {
+        t.Errorf("header is %q by %q at %d, expected %q by %q at %d", level.Name, level.Author, level.CreatedOn, expected.Name, expected.Author, expected.CreatedOn)
+    }
+    if level.Width != expected.Width || level.Length != expected.Length || level.Height != expected.Height {
+        t.Errorf("dimensions are %dx%dx%d, expected %dx%dx%d", level.Width, level.Length, level.Height, expected.Width, expected.Length, expected.Height)
+    }
+    if level.Spawn != expected.Spawn || level.WaterLevel != expected.WaterLevel {
+        t.Errorf("spawn %v water level %d, expected %v and %d", level.Spawn, level.WaterLevel, expected.Spawn, expected.WaterLevel)
+    }
+    if !bytes.Equal(int8sToBytes(level.Blocks), int8sToBytes(expected.Blocks)) {
+        t.Errorf("blocks changed in the round-trip")
+    }
+
+    if level.Player == nil {
+        t.Fatalf("player is missing")
+    }
+    if level.Player.X != expected.Player.X || level.Player.Y != expected.Player.Y || level.Player.Z != expected.Player.Z {
+        t.Errorf("player is at %v, %v, %v, expected %v, %v, %v", level.Player.X, level.Player.Y, level.Player.Z, expected.Player.X, expected.Player.Y, expected.Player.Z)
+    }
+    if level.Player.Arrows != expected.Player.Arrows || level.Player.Score != expected.Player.Score {
+        t.Errorf("player has %d arrows and %d score, expected %d and %d", level.Player.Arrows, level.Player.Score, expected.Player.Arrows, expected.Player.Score)
+    }
+    slots, _ := level.Player.Inventory["slots"].([]int32)
+    if len(slots) != 9 || slots[2] != 45 {
+        t.Errorf("player inventory slots are %v", slots)
+    }
+
+    mobs := level.Mobs()
+    if len(mobs) != 2 {
+        t.Fatalf("expected 2 mobs, got %d", len(mobs))
+    }
+    for i, mob := range mobs {
+        expectedMob := expected.Entities[i]
+        if mob.TextureName != expectedMob.TextureName || mob.X != expectedMob.X || mob.Y != expectedMob.Y || mob.Z != expectedMob.Z || mob.Health != expectedMob.Health {
+            t.Errorf("mob %d is %s at %v, %v, %v with %d health, expected %s at %v, %v, %v with %d", i, mob.TextureName, mob.X, mob.Y, mob.Z, mob.Health, expectedMob.TextureName, expectedMob.X, expectedMob.Y, expectedMob.Z, expectedMob.Health)
+        }
+    }
+    if !mobs[1].HasHair {
+        t.Errorf("the sheep lost its hair")
+    }
+}
+
+// TestClassicV2LevelGolden pins the uncompressed Java stream, run with -update to rewrite it after an intended change
+func TestClassicV2LevelGolden(t *testing.T) {
+    gzReader, err := gzip.NewReader(bytes.NewReader(writeTestClassicV2Level(t)))
+    if err != nil {
+        t.Fatal(err)
+    }
+    stream, err := io.ReadAll(gzReader)
+    if err != nil {
+        t.Fatal(err)
+    }
+
+    golden := filepath.Join("testdata", "classic_v2_level.golden")
+    if *update {
+        err = os.WriteFile(golden, stream, 0644)
+        if err != nil {
+            t.Fatal(err)
+        }
+    }
+    expected, err := os.ReadFile(golden)
+    if err != nil {
+        t.Fatal(err)
+    }
+    if !bytes.Equal(stream, expected) {
+        t.Errorf("the written stream differs from %s", golden)
+    }
+}
+
+func int8sToBytes(values []int8) []byte {
+    bytes := make([]byte, len(values))
+    for i, value := range values {
+        bytes[i] = byte(value)
+    }
+    return bytes
+}
@@ -0,0 +1,214 @@
+package classic_converter
+
+import (
+    "io"
+    "time"
+
+    "github.com/BJTMastermind/go-nbt"
+)
+
+// ClassicWorld is the ClassiCube (.cw) world format used by ClassiCube, MCGalaxy and most modern classic servers.
+type ClassicWorld struct {
+    // Root - About
+    Name string
+    UUID []int8
+    CreatedBy string
+    MapGenerator string
+    TimeCreated int64
+    // Root - Map
+    Width int16
+    Length int16
+    Height int16
+    Spawn [3]int16
+    SpawnYaw int8
+    SpawnPitch int8
+    Blocks []int8
+    // Metadata - CPE EnvColors, -1 means use the client default
+    SkyColor int32
+    FogColor int32
+    CloudColor int32
+}
+
+func (classic_world *ClassicWorld) InitWithDefaults() *ClassicWorld {
+    classic_world.Name = "A Nice World"
+    classic_world.UUID = newUUID()
+    classic_world.CreatedBy = ""
+    classic_world.MapGenerator = "Classic-Converter"
+    classic_world.TimeCreated = time.Now().Unix()
+    classic_world.Width = 256
+    classic_world.Length = 256
+    classic_world.Height = 64
+    classic_world.Spawn = [3]int16{128, 33, 128}
+    classic_world.SpawnYaw = 0
+    classic_world.SpawnPitch = 0
+    classic_world.Blocks = make([]int8, 256*256*64)
+    classic_world.SkyColor = -1
+    classic_world.FogColor = -1
+    classic_world.CloudColor = -1
+
+    return classic_world
+}
+
+func (classic_world *ClassicWorld) FromLevel(level *Level) {
+    classic_world.Name = level.Name
+    classic_world.CreatedBy = level.Author
+    classic_world.TimeCreated = level.CreatedOn / 1000
+    classic_world.Width = level.Width
+    classic_world.Length = level.Length
+    classic_world.Height = level.Height
+    classic_world.Blocks = level.Blocks
+    classic_world.SkyColor = level.SkyColor
+    classic_world.FogColor = level.FogColor
+    classic_world.CloudColor = level.CloudColor
+
+    if level.HasSpawn {
+        classic_world.Spawn = level.Spawn
+        classic_world.SpawnYaw = Degrees2Byte(level.SpawnYaw)
+        classic_world.SpawnPitch = Degrees2Byte(level.SpawnPitch)
+    } else {
+        classic_world.FindSpawn()
+    }
+}
+
+func (classic_world *ClassicWorld) FindSpawn() {
+    classic_world.Spawn = findSafeSpawn(classic_world.Blocks, classic_world.Width, classic_world.Length, classic_world.Height)
+}
+
+func (classic_world *ClassicWorld) WriteTo(w io.Writer) (int64, error) {
+    now := time.Now().Unix()
+
+    root := nbt.NewCompoundTag("ClassicWorld", map[string]nbt.Tag{
+        "FormatVersion": &nbt.Byte{
+            Value: 1,
+        },
+        "Name": &nbt.String{
+            Value: classic_world.Name,
+        },
+        "UUID": &nbt.ByteArray{
+            Value: classic_world.UUID,
+        },
+        "X": &nbt.Short{
+            Value: classic_world.Width,
+        },
+        "Y": &nbt.Short{
+            Value: classic_world.Height,
+        },
+        "Z": &nbt.Short{
+            Value: classic_world.Length,
+        },
+        "CreatedBy": &nbt.Compound{
+            Value: map[string]nbt.Tag{
+                "Service": &nbt.String{
+                    Value: "Minecraft",
+                },
+                "Username": &nbt.String{
+                    Value: classic_world.CreatedBy,
+                },
+            },
+        },
+        "MapGenerator": &nbt.Compound{
+            Value: map[string]nbt.Tag{
+                "Software": &nbt.String{
+                    Value: "Classic-Converter",
+                },
+                "MapGeneratorName": &nbt.String{
+                    Value: classic_world.MapGenerator,
+                },
+            },
+        },
+        "TimeCreated": &nbt.Long{
+            Value: classic_world.TimeCreated,
+        },
+        "LastAccessed": &nbt.Long{
+            Value: now,
+        },
+        "LastModified": &nbt.Long{
+            Value: now,
+        },
+        "Spawn": &nbt.Compound{
+            Value: map[string]nbt.Tag{
+                "X": &nbt.Short{
+                    Value: classic_world.Spawn[0],
+                },
+                "Y": &nbt.Short{
+                    Value: classic_world.Spawn[1],
+                },
+                "Z": &nbt.Short{
+                    Value: classic_world.Spawn[2],
+                },
+                "H": &nbt.Byte{
+                    Value: classic_world.SpawnYaw,
+                },
+                "P": &nbt.Byte{
+                    Value: classic_world.SpawnPitch,
+                },
+            },
+        },
+        "BlockArray": &nbt.ByteArray{
+            Value: classic_world.Blocks,
+        },
+        "Metadata": &nbt.Compound{
+            Value: map[string]nbt.Tag{
+                "CPE": &nbt.Compound{
+                    Value: map[string]nbt.Tag{
+                        "EnvColors": &nbt.Compound{
+                            Value: map[string]nbt.Tag{
+                                "ExtensionVersion": &nbt.Int{
+                                    Value: 1,
+                                },
+                                "Sky": color2Compound(classic_world.SkyColor),
+                                "Cloud": color2Compound(classic_world.CloudColor),
+                                "Fog": color2Compound(classic_world.FogColor),
+                                "Ambient": color2Compound(-1),
+                                "Sunlight": color2Compound(-1),
+                            },
+                        },
+                    },
+                },
+            },
+        },
+    })
+
+    stream := nbt.NewStream(nbt.BigEndian)
+
+    err := stream.WriteTag(root)
+    if err != nil {
+        return 0, err
+    }
+
+    data, err := nbt.Compress(stream, nbt.CompressGZip, nbt.DefaultCompressionLevel)
+    if err != nil {
+        return 0, err
+    }
+
+    n, err := w.Write(data)
+    return int64(n), err
+}
+
+func (classic_world *ClassicWorld) WriteToFile(filename string) error {
+    return writeFile(filename, classic_world)
+}
+
+// Splits a 0xRRGGBB color into the R, G and B shorts used by CPE EnvColors, -1 keeps the client default
+func color2Compound(color int32) *nbt.Compound {
+    r, g, b := int16(-1), int16(-1), int16(-1)
+    if color >= 0 {
+        r = int16((color >> 16) & 0xff)
+        g = int16((color >> 8) & 0xff)
+        b = int16(color & 0xff)
+    }
+
+    return &nbt.Compound{
+        Value: map[string]nbt.Tag{
+            "R": &nbt.Short{
+                Value: r,
+            },
+            "G": &nbt.Short{
+                Value: g,
+            },
+            "B": &nbt.Short{
+                Value: b,
+            },
+        },
+    }
+}
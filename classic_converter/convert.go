@@ -0,0 +1,96 @@
+package classic_converter
+
+import (
+    "bytes"
+    "context"
+    "io"
+    "strings"
+)
+
+// Convert reads a level from r and writes it to w in options.Format, without touching the filesystem.
+// How far it is gets reported to options.Progress and it stops with ctx's error once ctx is cancelled.
+// The input format is detected from the content unless options.InputFormat is set.
+// Formats written as a folder are written as a zip archive of the folder.
+func Convert(ctx context.Context, r io.Reader, w io.Writer, options Options) error {
+    outputFormat, ok := FormatByName(options.Format)
+    if !ok || outputFormat.NewWriter == nil {
+        return newError(ErrUnknownFormat, options.Format, nil, "error: Unknown format %s. Expected one of %s", options.Format, strings.Join(WritableFormats(), ", "))
+    }
+    // There is nowhere to put a sidecar, callers write it themselves from the level's Metadata
+    if options.Metadata == MetadataSidecar {
+        return newError(ErrInvalidOption, outputFormat.Name, nil, "error: Convert can't write a metadata sidecar, embed it or write it with LevelMetadata's WriteTo.")
+    }
+    err := outputFormat.CheckOptions(options)
+    if err != nil {
+        return err
+    }
+    writer, _, err := outputFormat.NewWriter(options)
+    if err != nil {
+        return err
+    }
+
+    content, err := io.ReadAll(&progressReader{ctx: ctx, reader: r, progress: options.Progress, total: readerSize(r)})
+    if err != nil {
+        return err
+    }
+    if err := ctx.Err(); err != nil {
+        return err
+    }
+
+    inputFormat, err := contentFormat(content, options.InputFormat)
+    if err != nil {
+        return err
+    }
+    level, err := inputFormat.Reader.ReadLevel(bytes.NewReader(content))
+    if err != nil {
+        return readError(inputFormat.Name, err)
+    }
+    if level.Name == "" {
+        level.Name = "A Nice World"
+    }
+    level.logHeader()
+    if err := ctx.Err(); err != nil {
+        return err
+    }
+
+    blockCount := int64(len(level.Blocks))
+    reportProgress(options.Progress, "Converting", 0, blockCount)
+    level, err = ApplyEnvironment(level, options)
+    if err != nil {
+        return err
+    }
+    mapped, err := outputFormat.MapBlocks(level, options)
+    if err != nil {
+        return err
+    }
+    writer.FromLevel(mapped)
+    outputFormat.LogSkipped(level)
+    reportProgress(options.Progress, "Converting", blockCount, blockCount)
+    if err := ctx.Err(); err != nil {
+        return err
+    }
+
+    TrackProgress(ctx, writer, options.Progress)
+    _, err = writer.WriteTo(&progressWriter{ctx: ctx, writer: w, progress: options.Progress})
+    return writeError(outputFormat.Name, err)
+}
+
+// Picks the format to read the content with, the named one or the one it looks most like
+func contentFormat(content []byte, name string) (Format, error) {
+    if name != "" {
+        format, ok := FormatByName(name)
+        if !ok || format.Reader == nil {
+            return Format{}, newError(ErrUnknownFormat, name, nil, "error: Unknown input format %s.", name)
+        }
+        return format, nil
+    }
+
+    detections := detectContent(content)
+    if format, _, ok := readableDetection(detections); ok {
+        return format, nil
+    }
+    if len(detections) > 0 {
+        return Format{}, newError(ErrUnknownFormat, detections[0].Format, nil, "error: Input looks like a %s, which can't be converted from.", detections[0].Kind)
+    }
+    return Format{}, newError(ErrUnknownFormat, "", nil, "error: Could not tell what format the input is.")
+}
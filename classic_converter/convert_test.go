@@ -0,0 +1,76 @@
+package classic_converter
+
+import (
+    "bytes"
+    "context"
+    "errors"
+    "io"
+    "reflect"
+    "testing"
+)
+
+func TestConvertCancelled(t *testing.T) {
+    ctx, cancel := context.WithCancel(context.Background())
+    cancel()
+
+    options := *new(Options).InitWithDefaults()
+    options.Format = "schematic"
+    err := Convert(ctx, bytes.NewReader(writeTestFormat(t, "classic_v1", testDetectLevel())), io.Discard, options)
+    if !errors.Is(err, context.Canceled) {
+        t.Errorf("Convert returned %v, expected context.Canceled", err)
+    }
+}
+
+// A report Convert made, recorded to check the phases
+type recordedReport struct {
+    phase string
+    processed int64
+    total int64
+}
+
+func TestConvertProgress(t *testing.T) {
+    input := writeTestFormat(t, "classic_v1", testDetectLevel())
+
+    reports := []recordedReport{}
+    options := *new(Options).InitWithDefaults()
+    options.Format = "stl_model"
+    options.Progress = ProgressFunc(func(phase string, processed int64, total int64) {
+        reports = append(reports, recordedReport{phase, processed, total})
+    })
+
+    output := new(bytes.Buffer)
+    err := Convert(context.Background(), bytes.NewReader(input), output, options)
+    if err != nil {
+        t.Fatal(err)
+    }
+
+    // Phases come one after the other and each one ends at its total, the written size is only known at the end
+    phases := []string{}
+    last := map[string]recordedReport{}
+    for _, report := range reports {
+        if len(phases) == 0 || phases[len(phases) - 1] != report.phase {
+            phases = append(phases, report.phase)
+        }
+        if previous, ok := last[report.phase]; ok && report.processed < previous.processed {
+            t.Errorf("%s went back from %d to %d", report.phase, previous.processed, report.processed)
+        }
+        last[report.phase] = report
+    }
+    if !reflect.DeepEqual(phases, []string{"Reading", "Converting", "Building", "Writing"}) {
+        t.Fatalf("phases were %v", phases)
+    }
+
+    expected := map[string]recordedReport{
+        "Reading": {"Reading", int64(len(input)), int64(len(input))},
+        "Converting": {"Converting", 32*32*32, 32*32*32},
+        "Writing": {"Writing", int64(output.Len()), 0},
+    }
+    for phase, report := range expected {
+        if last[phase] != report {
+            t.Errorf("%s ended at %v, expected %v", phase, last[phase], report)
+        }
+    }
+    if building := last["Building"]; building.total == 0 || building.processed != building.total {
+        t.Errorf("Building ended at %d of %d", building.processed, building.total)
+    }
+}
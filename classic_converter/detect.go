@@ -0,0 +1,176 @@
+package classic_converter
+
+import (
+    "bytes"
+    "compress/gzip"
+    "encoding/binary"
+    "io"
+    "math"
+    "sort"
+)
+
+// Sniffer checks if the content of a file looks like its format. Gzipped files are decompressed first.
+// It returns how sure it is from 0 to 1 and what kind of file it found, an empty kind means the format's description.
+type Sniffer func(content []byte) (float64, string)
+
+// Detection is a format a file might be in
+type Detection struct {
+    Format string
+    Kind string
+    Confidence float64 // 0 to 1
+}
+
+// Detect works out what format the content is in by looking at it, ignoring its name.
+// Every format that matched is returned, most likely first.
+func Detect(reader io.ReaderAt) ([]Detection, error) {
+    content, err := io.ReadAll(io.NewSectionReader(reader, 0, math.MaxInt64))
+    if err != nil {
+        return nil, err
+    }
+    return detectContent(content), nil
+}
+
+func detectContent(content []byte) []Detection {
+    // Whatever could be decompressed is sniffed, so truncated files still get detected
+    if len(content) >= 2 && content[0] == 0x1f && content[1] == 0x8b {
+        gzReader, err := gzip.NewReader(bytes.NewReader(content))
+        if err == nil {
+            decompressed, _ := io.ReadAll(gzReader)
+            content = decompressed
+        }
+    }
+
+    detections := []Detection{}
+    for _, format := range registeredFormats {
+        if format.Sniff == nil {
+            continue
+        }
+        confidence, kind := format.Sniff(content)
+        if confidence <= 0 {
+            continue
+        }
+        detections = append(detections, Detection{format.Name, ternary(kind != "", kind, format.Description), confidence})
+    }
+    sort.SliceStable(detections, func(i, j int) bool {
+        return detections[i].Confidence > detections[j].Confidence
+    })
+    return detections
+}
+
+func sniffMagic(magic []byte, confidence float64) Sniffer {
+    return func(content []byte) (float64, string) {
+        return ternary(bytes.HasPrefix(content, magic), confidence, 0), ""
+    }
+}
+
+// Big endian NBT files start with the compound tag ID and the root's name
+func sniffNBTRoot(name string) Sniffer {
+    magic := []byte{0x0a, 0x00, 0x00}
+    binary.BigEndian.PutUint16(magic[1:], uint16(len(name)))
+    return sniffMagic(append(magic, name...), 0.95)
+}
+
+// Pre-classic levels are nothing but 256x256x64 blocks, so all there is to check is the size and the block IDs
+func sniffPreClassic(content []byte) (float64, string) {
+    if len(content) != 256*256*64 {
+        return 0, ""
+    }
+    for _, block := range content {
+        if block > byte(blockTables["pre_classic"].MaxBlock) {
+            return 0, ""
+        }
+    }
+    return 0.8, ""
+}
+
+func sniffClassicV1(content []byte) (float64, string) {
+    if !bytes.HasPrefix(content, []byte{0x27, 0x1b, 0xb7, 0x88, 0x01}) {
+        return 0, ""
+    }
+
+    // The blocks take up the rest of the file after the header
+    reader := bytes.NewReader(content[5:])
+    var nameLength, creatorLength uint16
+    var createTime int64
+    var dimensions [3]int16
+    binary.Read(reader, binary.BigEndian, &nameLength)
+    reader.Seek(int64(nameLength), io.SeekCurrent)
+    binary.Read(reader, binary.BigEndian, &creatorLength)
+    reader.Seek(int64(creatorLength), io.SeekCurrent)
+    binary.Read(reader, binary.BigEndian, &createTime)
+    err := binary.Read(reader, binary.BigEndian, &dimensions)
+    if err != nil || reader.Len() != int(dimensions[0]) * int(dimensions[1]) * int(dimensions[2]) {
+        return 0.6, ""
+    }
+    return 1, ""
+}
+
+// Client and server levels are the same serialized Level, only client levels have a player in them
+func sniffClassicV2(content []byte) (float64, string) {
+    if !bytes.HasPrefix(content, []byte{0x27, 0x1b, 0xb7, 0x88, 0x02}) {
+        return 0, ""
+    }
+    if !bytes.HasPrefix(content[5:], []byte{0xac, 0xed}) {
+        return 0.6, ""
+    }
+    if bytes.Contains(content, []byte("com.mojang.minecraft.player.Player")) {
+        return 1, "Classic version 2 client level"
+    }
+    return 1, "Classic version 2 server level"
+}
+
+// The 18 byte header is followed by the blocks, newer levels add more sections after them
+func sniffMCGalaxyLevel(content []byte) (float64, string) {
+    if len(content) < 18 || binary.LittleEndian.Uint16(content) != 1874 {
+        return 0, ""
+    }
+    width := int(binary.LittleEndian.Uint16(content[2:]))
+    length := int(binary.LittleEndian.Uint16(content[4:]))
+    height := int(binary.LittleEndian.Uint16(content[6:]))
+    return ternary(len(content) >= 18 + width * length * height, 0.9, 0.3), ""
+}
+
+// Bedrock structures are little endian NBT with an unnamed root
+func sniffBedrockStructure(content []byte) (float64, string) {
+    if !bytes.HasPrefix(content, []byte{0x0a, 0x00, 0x00}) || !bytes.Contains(content, []byte("format_version")) || !bytes.Contains(content, []byte("structure_world_origin")) {
+        return 0, ""
+    }
+    return 0.9, ""
+}
+
+// Binary STL files are an 80 byte header, a triangle count and 50 bytes per triangle
+func sniffSTL(content []byte) (float64, string) {
+    if len(content) < 84 || 84 + int(binary.LittleEndian.Uint32(content[80:])) * 50 != len(content) {
+        return 0, ""
+    }
+    return 0.7, ""
+}
+
+// OBJ files are text, so anything with a zero byte near the start is something else
+func sniffWavefrontOBJ(content []byte) (float64, string) {
+    if bytes.IndexByte(content[:clamp(len(content), 0, 512)], 0) >= 0 || !bytes.Contains(content, []byte("\nv ")) || !bytes.Contains(content, []byte("\nf ")) {
+        return 0, ""
+    }
+    return 0.5, ""
+}
+
+func sniffRender(content []byte) (float64, string) {
+    switch {
+    case bytes.HasPrefix(content, []byte("\x89PNG\r\n\x1a\n")):
+        return 0.9, "PNG picture"
+    case bytes.HasPrefix(content, []byte("GIF89a")):
+        return 0.9, "GIF animation"
+    }
+    return 0, ""
+}
+
+func sniffWorldDump(content []byte) (float64, string) {
+    content = bytes.TrimSpace(content)
+    switch {
+    case bytes.HasPrefix(content, []byte(`{"type":"level"`)):
+        return 0.9, "NDJSON dump of a world"
+    case bytes.HasPrefix(content, []byte("{")) && bytes.Contains(content, []byte(`"dimensions"`)):
+        return 0.7, ""
+    }
+    return 0, ""
+}
@@ -0,0 +1,174 @@
+package classic_converter
+
+import (
+    "bytes"
+    "compress/gzip"
+    "os"
+    "path/filepath"
+    "testing"
+)
+
+// Writes the level the way the format's writer does
+func writeTestFormat(t *testing.T, name string, level *Level) []byte {
+    format, ok := FormatByName(name)
+    if !ok {
+        t.Fatalf("no %s format", name)
+    }
+    writer, _, err := format.NewWriter(*new(Options).InitWithDefaults())
+    if err != nil {
+        t.Fatal(err)
+    }
+    writer.FromLevel(level)
+
+    buffer := new(bytes.Buffer)
+    _, err = writer.WriteTo(buffer)
+    if err != nil {
+        t.Fatalf("writing the %s failed: %v", name, err)
+    }
+    return buffer.Bytes()
+}
+
+// testDetectLevel is big and varied enough that its blocks don't compress down to a few bytes
+func testDetectLevel() *Level {
+    level := new(Level).InitWithDefaults()
+    level.Name = "Detect"
+    level.Width, level.Length, level.Height = 32, 32, 32
+    level.Blocks = make([]int8, 32*32*32)
+    for i := range level.Blocks {
+        level.Blocks[i] = int8((i * 7919) % 50)
+    }
+    return level
+}
+
+func TestDetect(t *testing.T) {
+    classicV1 := writeTestFormat(t, "classic_v1", testDetectLevel())
+
+    serverLevel := testClassicV2Level()
+    serverLevel.Player = nil
+    serverLevel.Entities = serverLevel.Mobs()
+
+    preClassic := new(bytes.Buffer)
+    gzWriter := gzip.NewWriter(preClassic)
+    gzWriter.Write(make([]byte, 256*256*64))
+    gzWriter.Close()
+
+    tests := []struct {
+        name string
+        content []byte
+        format string // Empty when nothing should be detected
+        kind string
+        confidence float64
+    }{
+        {
+            name: "classic v1",
+            content: classicV1,
+            format: "classic_v1",
+            kind: "Classic version 1 level (0.0.13a - 0.0.23a)",
+            confidence: 1,
+        },
+        {
+            name: "truncated gzip of a classic v1 level",
+            content: classicV1[:len(classicV1) / 2],
+            format: "classic_v1",
+            kind: "Classic version 1 level (0.0.13a - 0.0.23a)",
+            confidence: 0.6,
+        },
+        {
+            name: "classic v2 client level",
+            content: writeTestFormat(t, "classic_v2", testClassicV2Level()),
+            format: "classic_v2",
+            kind: "Classic version 2 client level",
+            confidence: 1,
+        },
+        {
+            name: "classic v2 server level",
+            content: writeTestFormat(t, "classic_v2", serverLevel),
+            format: "classic_v2",
+            kind: "Classic version 2 server level",
+            confidence: 1,
+        },
+        {
+            name: "classic v2 magic without a Java stream",
+            content: []byte{0x27, 0x1b, 0xb7, 0x88, 0x02, 0x00, 0x00},
+            format: "classic_v2",
+            kind: "Classic version 2 level (0.0.24 - 0.30)",
+            confidence: 0.6,
+        },
+        {
+            name: "pre-classic",
+            content: preClassic.Bytes(),
+            format: "pre_classic",
+            kind: "Pre-classic (rd-132211 - 0.0.12a) level, 256x256x64 raw blocks",
+            confidence: 0.8,
+        },
+        {
+            name: "schematic",
+            content: writeTestFormat(t, "schematic", testDetectLevel()),
+            format: "schematic",
+            kind: "MCEdit schematic",
+            confidence: 0.95,
+        },
+        {
+            name: "garbage",
+            content: []byte("\x00\x01 not a world"),
+        },
+    }
+
+    for _, test := range tests {
+        t.Run(test.name, func(t *testing.T) {
+            detections, err := Detect(bytes.NewReader(test.content))
+            if err != nil {
+                t.Fatal(err)
+            }
+            if test.format == "" {
+                if len(detections) > 0 {
+                    t.Errorf("expected nothing to be detected, got %v", detections)
+                }
+                return
+            }
+            if len(detections) == 0 {
+                t.Fatalf("nothing was detected, expected %s", test.format)
+            }
+            expected := Detection{test.format, test.kind, test.confidence}
+            if detections[0] != expected {
+                t.Errorf("detected %v, expected %v", detections[0], expected)
+            }
+        })
+    }
+}
+
+func TestDetectFileIgnoresExtension(t *testing.T) {
+    tests := []struct {
+        filename string
+        format string
+        content []byte
+    }{
+        {"classic_v1.schematic", "classic_v1", writeTestFormat(t, "classic_v1", testDetectLevel())},
+        {"schematic.dat", "schematic", writeTestFormat(t, "schematic", testDetectLevel())},
+        {"indev.mine", "indev_level", writeTestFormat(t, "indev_level", testDetectLevel())},
+        {"classic_v2.mclevel", "classic_v2", writeTestFormat(t, "classic_v2", testClassicV2Level())},
+        {"no_extension", "classic_v1", writeTestFormat(t, "classic_v1", testDetectLevel())},
+    }
+
+    for _, test := range tests {
+        t.Run(test.filename, func(t *testing.T) {
+            filename := filepath.Join(t.TempDir(), test.filename)
+            err := os.WriteFile(filename, test.content, 0644)
+            if err != nil {
+                t.Fatal(err)
+            }
+
+            format, _, err := DetectFile(filename)
+            if err != nil {
+                t.Fatal(err)
+            }
+            if format.Name != test.format {
+                t.Errorf("%s was detected as %s, expected %s", test.filename, format.Name, test.format)
+            }
+            _, err = format.ReadFile(filename)
+            if err != nil {
+                t.Errorf("reading %s as %s failed: %v", test.filename, format.Name, err)
+            }
+        })
+    }
+}
@@ -0,0 +1,97 @@
+package classic_converter
+
+import (
+    "fmt"
+    "strconv"
+    "strings"
+)
+
+// Where players spawn, set with Options.Spawn. Anything else is a block position written as "x,y,z"
+const (
+    SpawnKeep = "" // The level's spawn, writers find a safe spot when it has none
+    SpawnSafe = "safe" // A safe spot found by the writer, even when the level has a spawn
+    SpawnCenter = "center" // On top of the middle of the world
+)
+
+// ApplyEnvironment returns a copy of the level with the colors, water level and spawn the options ask for.
+// Positions outside the level are only found out here, everything else is checked by CheckOptions.
+func ApplyEnvironment(level *Level, options Options) (*Level, error) {
+    err := checkEnvironment(options)
+    if err != nil {
+        return nil, err
+    }
+
+    changed := *level
+    for _, color := range []struct {
+        option string
+        value *int32
+    }{
+        {options.SkyColor, &changed.SkyColor},
+        {options.FogColor, &changed.FogColor},
+        {options.CloudColor, &changed.CloudColor},
+    } {
+        if color.option != "" {
+            *color.value, _ = parseColor(color.option)
+        }
+    }
+    if options.WaterLevel >= 0 {
+        changed.WaterLevel = int32(options.WaterLevel)
+    }
+
+    switch options.Spawn {
+    case SpawnKeep:
+    case SpawnSafe:
+        changed.HasSpawn = false
+    case SpawnCenter:
+        x := int32(level.Width / 2)
+        z := int32(level.Length / 2)
+        y := getHightestTile(level.Blocks, level.Width, level.Length, level.Height, x, z) + 1
+        changed.HasSpawn = true
+        changed.Spawn = [3]int16{int16(x), int16(y), int16(z)}
+    default:
+        spawn, _ := parseSpawn(options.Spawn)
+        if spawn[0] < 0 || spawn[0] >= level.Width || spawn[1] < 0 || spawn[1] >= level.Height || spawn[2] < 0 || spawn[2] >= level.Length {
+            return nil, newError(ErrInvalidOption, "", nil, "error: Spawn %s is outside the %dx%dx%d world.", options.Spawn, level.Width, level.Height, level.Length)
+        }
+        changed.HasSpawn = true
+        changed.Spawn = spawn
+    }
+    return &changed, nil
+}
+
+// Checks the environment options can be read, without needing the level
+func checkEnvironment(options Options) error {
+    for _, color := range [][2]string{{"sky", options.SkyColor}, {"fog", options.FogColor}, {"cloud", options.CloudColor}} {
+        if color[1] == "" {
+            continue
+        }
+        if _, err := parseColor(color[1]); err != nil {
+            return newError(ErrInvalidOption, "", err, "error: The %s color must be written as RRGGBB or be \"default\". Got %s", color[0], color[1])
+        }
+    }
+    if options.Spawn != SpawnKeep && options.Spawn != SpawnSafe && options.Spawn != SpawnCenter {
+        if _, err := parseSpawn(options.Spawn); err != nil {
+            return newError(ErrInvalidOption, "", err, "error: Spawn must be \"safe\", \"center\" or a block position written as \"x,y,z\". Got %s", options.Spawn)
+        }
+    }
+    return nil
+}
+
+// Reads a RRGGBB color, "default" is the client's own color (-1)
+func parseColor(color string) (int32, error) {
+    if color == "default" {
+        return -1, nil
+    }
+    color = strings.TrimPrefix(color, "#")
+    if len(color) != 6 {
+        return 0, fmt.Errorf("%s is not 6 hex digits", color)
+    }
+    value, err := strconv.ParseUint(color, 16, 32)
+    return int32(value), err
+}
+
+func parseSpawn(spawn string) ([3]int16, error) {
+    var position [3]int16
+    _, err := fmt.Sscanf(spawn, "%d,%d,%d", &position[0], &position[1], &position[2])
+    return position, err
+}
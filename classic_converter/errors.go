@@ -0,0 +1,91 @@
+package classic_converter
+
+import (
+    "compress/flate"
+    "compress/gzip"
+    "context"
+    "errors"
+    "fmt"
+    "io"
+)
+
+// What went wrong, every error the converter returns matches one of these with errors.Is
+var (
+    ErrUnknownFormat = errors.New("unknown format") // The input isn't in a format that can be read, or the format asked for doesn't exist
+    ErrUnsupportedVersion = errors.New("unsupported version") // The format is known but not the version the input or option is in
+    ErrTruncated = errors.New("truncated data") // The input ends before everything in it could be read
+    ErrInvalidDimensions = errors.New("invalid dimensions") // The world's size doesn't match its blocks or can't be used
+    ErrWriteFailed = errors.New("write failed") // The output couldn't be made or written
+    ErrUnmappableBlock = errors.New("unmappable block") // A block has no counterpart in the format
+    ErrInvalidOption = errors.New("invalid option") // An option has a value that can't be used
+)
+
+// Error is an error of one of the Err kinds, get it with errors.As for the format it happened in and what caused it
+type Error struct {
+    Kind error // One of the Err variables
+    Format string // Format being read or written, empty when it isn't known
+    Message string
+    Err error // What caused it, nil when nothing else did
+}
+
+func (err *Error) Error() string {
+    return err.Message
+}
+
+// Unwrap makes both the kind and the cause match with errors.Is and errors.As
+func (err *Error) Unwrap() []error {
+    if err.Err == nil {
+        return []error{err.Kind}
+    }
+    return []error{err.Kind, err.Err}
+}
+
+func newError(kind error, format string, cause error, message string, args ...any) *Error {
+    return &Error{kind, format, fmt.Sprintf(message, args...), cause}
+}
+
+// Sorts out errors from reading a format, running out of data is ErrTruncated and anything else is kept as it is
+func readError(format string, err error) error {
+    var converterError *Error
+    var corrupt flate.CorruptInputError
+    switch {
+    case errors.As(err, &converterError):
+        return err
+    case errors.Is(err, io.EOF), errors.Is(err, io.ErrUnexpectedEOF), errors.Is(err, gzip.ErrChecksum), errors.As(err, &corrupt):
+        return newError(ErrTruncated, format, err, "error: The input ends before it was all read, %s.", err)
+    }
+    return err
+}
+
+// Checks that the blocks fill the dimensions, fewer blocks means the input was cut short
+func checkBlockCount(format string, description string, width int16, length int16, height int16, blocks int) error {
+    expected := int(width) * int(length) * int(height)
+    if width <= 0 || length <= 0 || height <= 0 {
+        return newError(ErrInvalidDimensions, format, nil, "error: Not a vaild %s, it is %dx%dx%d blocks.", description, width, height, length)
+    }
+    if blocks < expected {
+        return newError(ErrTruncated, format, nil, "error: Not a vaild %s, expected %d blocks but got %d.", description, expected, blocks)
+    }
+    if blocks > expected {
+        return newError(ErrInvalidDimensions, format, nil, "error: Not a vaild %s, expected %d blocks but got %d.", description, expected, blocks)
+    }
+    return nil
+}
+
+// Errors writing a file are ErrWriteFailed naming the file, the converter's own errors are left as they are
+func fileError(filename string, err error) error {
+    var converterError *Error
+    if errors.As(err, &converterError) {
+        return err
+    }
+    return newError(ErrWriteFailed, "", err, "error: Could not write %s, %s.", filename, err)
+}
+
+// Errors writing the output are ErrWriteFailed, cancelled conversions are left as they are
+func writeError(format string, err error) error {
+    var converterError *Error
+    if err == nil || errors.As(err, &converterError) || errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+        return err
+    }
+    return newError(ErrWriteFailed, format, err, "error: Could not write the %s, %s.", format, err)
+}
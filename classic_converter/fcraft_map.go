@@ -0,0 +1,170 @@
+package classic_converter
+
+import (
+    "bytes"
+    "compress/flate"
+    "encoding/binary"
+    "io"
+    "strconv"
+    "time"
+)
+
+// FCraftMap is the fCraft map format version 3 (.fcm) used by fCraft, ProCraft and their forks.
+type FCraftMap struct {
+    Width int16
+    Length int16
+    Height int16
+    Spawn [3]int16
+    SpawnYaw int8
+    SpawnPitch int8
+    DateCreated int64
+    GUID []int8
+    Blocks []int8
+    // Metadata is stored as group -> key -> value
+    Metadata map[string]map[string]string
+}
+
+func (fcraft_map *FCraftMap) InitWithDefaults() *FCraftMap {
+    fcraft_map.Width = 256
+    fcraft_map.Length = 256
+    fcraft_map.Height = 64
+    fcraft_map.Spawn = [3]int16{128, 33, 128}
+    fcraft_map.SpawnYaw = 0
+    fcraft_map.SpawnPitch = 0
+    fcraft_map.DateCreated = time.Now().Unix()
+    fcraft_map.GUID = newUUID()
+    fcraft_map.Blocks = make([]int8, 256*256*64)
+    fcraft_map.Metadata = map[string]map[string]string{}
+
+    return fcraft_map
+}
+
+func (fcraft_map *FCraftMap) FromLevel(level *Level) {
+    fcraft_map.Width = level.Width
+    fcraft_map.Length = level.Length
+    fcraft_map.Height = level.Height
+    fcraft_map.Blocks = level.Blocks
+    fcraft_map.SetCreator(level.Author, level.CreatedOn)
+
+    // The classic player's view is a better guess than the spawn rotation, which is only ever a yaw
+    if level.HasSpawn {
+        fcraft_map.Spawn = level.Spawn
+        fcraft_map.SpawnYaw = Degrees2Byte(level.SpawnYaw)
+        fcraft_map.SpawnPitch = Degrees2Byte(level.SpawnPitch)
+        if level.Player != nil {
+            fcraft_map.SpawnYaw = Degrees2Byte(level.Player.YRot)
+            fcraft_map.SpawnPitch = Degrees2Byte(level.Player.XRot)
+        }
+    } else {
+        fcraft_map.FindSpawn()
+    }
+}
+
+func (fcraft_map *FCraftMap) FindSpawn() {
+    fcraft_map.Spawn = findSafeSpawn(fcraft_map.Blocks, fcraft_map.Width, fcraft_map.Length, fcraft_map.Height)
+}
+
+// Adds the world's creator and creation date as metadata entries in the "Classic" group
+func (fcraft_map *FCraftMap) SetCreator(creator string, createdOn int64) {
+    if fcraft_map.Metadata["Classic"] == nil {
+        fcraft_map.Metadata["Classic"] = map[string]string{}
+    }
+    fcraft_map.Metadata["Classic"]["Creator"] = creator
+    fcraft_map.Metadata["Classic"]["CreatedOn"] = strconv.FormatInt(createdOn, 10)
+    fcraft_map.DateCreated = createdOn / 1000
+}
+
+func (fcraft_map *FCraftMap) WriteTo(w io.Writer) (int64, error) {
+    buffer := new(bytes.Buffer)
+
+    // Header, everything is little endian and spawn is in 1/32 of a block at the player's eye level
+    header := []any{
+        uint32(0x0FC2AF40), // Magic
+        uint8(13), // Revision
+        fcraft_map.Width,
+        fcraft_map.Height,
+        fcraft_map.Length,
+        int32(fcraft_map.Spawn[0]) * 32 + 16,
+        int32(fcraft_map.Spawn[1]) * 32 + 51,
+        int32(fcraft_map.Spawn[2]) * 32 + 16,
+        fcraft_map.SpawnYaw,
+        fcraft_map.SpawnPitch,
+        uint32(time.Now().Unix()), // Date modified
+        uint32(fcraft_map.DateCreated),
+        fcraft_map.GUID,
+        uint8(1), // Layer count
+    }
+    headerSize := 0
+    for _, value := range header {
+        headerSize += binary.Size(value)
+    }
+    // Layer index
+    layerIndexSize := 25
+    header = append(header,
+        uint8(0), // Layer type (blocks)
+        int64(headerSize + layerIndexSize + 4), // Offset of compressed data, after the index and metadata count
+        int32(0), // Compressed length, filled in once known
+        int32(0), // General purpose field
+        int32(1), // Element size
+        int32(len(fcraft_map.Blocks)), // Element count
+        int32(fcraft_map.metadataCount()),
+    )
+    for _, value := range header {
+        err := binary.Write(buffer, binary.LittleEndian, value)
+        if err != nil {
+            return 0, err
+        }
+    }
+
+    // Metadata and blocks are raw deflated together
+    dataStart := buffer.Len()
+    flateWriter, err := flate.NewWriter(buffer, flate.DefaultCompression)
+    if err != nil {
+        return 0, err
+    }
+
+    // Sorted so the same world always gives the same file
+    for _, group := range sortedKeys(fcraft_map.Metadata) {
+        entries := fcraft_map.Metadata[group]
+        for _, key := range sortedKeys(entries) {
+            for _, str := range []string{group, key, entries[key]} {
+                err = binary.Write(flateWriter, binary.LittleEndian, int32(len(str)))
+                if err != nil {
+                    return 0, err
+                }
+                _, err = flateWriter.Write([]byte(str))
+                if err != nil {
+                    return 0, err
+                }
+            }
+        }
+    }
+
+    err = binary.Write(flateWriter, binary.LittleEndian, fcraft_map.Blocks)
+    if err != nil {
+        return 0, err
+    }
+
+    err = flateWriter.Close()
+    if err != nil {
+        return 0, err
+    }
+
+    data := buffer.Bytes()
+    binary.LittleEndian.PutUint32(data[headerSize + 9:], uint32(len(data) - dataStart))
+
+    n, err := w.Write(data)
+    return int64(n), err
+}
+
+func (fcraft_map *FCraftMap) WriteToFile(filename string) error {
+    return writeFile(filename, fcraft_map)
+}
+
+func (fcraft_map *FCraftMap) metadataCount() int {
+    count := 0
+    for _, entries := range fcraft_map.Metadata {
+        count += len(entries)
+    }
+    return count
+}
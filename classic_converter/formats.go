@@ -0,0 +1,306 @@
+package classic_converter
+
+import (
+    "fmt"
+    "io"
+    "os"
+    "path/filepath"
+    "strings"
+)
+
+// The formats built into the converter, in the order they are listed
+func init() {
+    RegisterFormat(Format{
+        Name: "indev_level",
+        Description: "Minecraft Indev level",
+        Extensions: []string{".mclevel"},
+        Sniff: sniffNBTRoot("MinecraftLevel"),
+        Capabilities: Capabilities{Entities: true, Player: true, DataValues: true, Metadata: true},
+        BlockTable: "indev",
+        Reader: ReaderFunc(ReadIndevLevel),
+        NewWriter: func(options Options) (Writer, string, error) {
+            indevLevel := new(IndevLevel).InitWithDefaults()
+            indevLevel.EmbedMetadata = options.Metadata == MetadataEmbed
+            return indevLevel, ".mclevel", nil
+        },
+    })
+    RegisterFormat(Format{
+        Name: "schematic",
+        Description: "MCEdit schematic",
+        Extensions: []string{".schematic"},
+        Sniff: sniffNBTRoot("Schematic"),
+        Capabilities: Capabilities{Entities: true, DataValues: true, Metadata: true},
+        BlockTable: "alpha",
+        Reader: ReaderFunc(ReadSchematic),
+        NewWriter: func(options Options) (Writer, string, error) {
+            schematic := new(Schematic).InitWithDefaults()
+            schematic.EmbedMetadata = options.Metadata == MetadataEmbed
+            return schematic, ".schematic", nil
+        },
+    })
+    RegisterFormat(Format{
+        Name: "classic_world",
+        Description: "ClassiCube world",
+        Extensions: []string{".cw"},
+        Sniff: sniffNBTRoot("ClassicWorld"),
+        NewWriter: func(options Options) (Writer, string, error) {
+            return new(ClassicWorld).InitWithDefaults(), ".cw", nil
+        },
+    })
+    RegisterFormat(Format{
+        Name: "mcgalaxy_level",
+        Description: "MCGalaxy level",
+        Extensions: []string{".lvl"},
+        Sniff: sniffMCGalaxyLevel,
+        NewWriter: func(options Options) (Writer, string, error) {
+            return new(MCGalaxyLevel).InitWithDefaults(), ".lvl", nil
+        },
+    })
+    RegisterFormat(Format{
+        Name: "fcraft_map",
+        Description: "fCraft map",
+        Extensions: []string{".fcm"},
+        Sniff: sniffMagic([]byte{0x40, 0xaf, 0xc2, 0x0f}, 0.95), // 0x0FC2AF40 little endian
+        NewWriter: func(options Options) (Writer, string, error) {
+            return new(FCraftMap).InitWithDefaults(), ".fcm", nil
+        },
+    })
+    RegisterFormat(Format{
+        Name: "classic_v1",
+        Description: "Classic version 1 level (0.0.13a - 0.0.23a)",
+        Extensions: []string{".dat"},
+        Sniff: sniffClassicV1,
+        BlockTable: "0.0.23a",
+        Reader: ReaderFunc(ReadClassicLevel),
+        NewWriter: func(options Options) (Writer, string, error) {
+            classicLevel := new(ClassicV1Level).InitWithDefaults()
+            // The version only picks the block table, MapBlocks downgrades the blocks
+            err := checkClassicVersion(options.TargetVersion)
+            if err != nil {
+                return nil, "", err
+            }
+            return classicLevel, "_v1.dat", nil
+        },
+    })
+    RegisterFormat(Format{
+        Name: "classic_v2",
+        Description: "Classic version 2 level (0.0.24 - 0.30)",
+        Extensions: []string{".mine", ".dat"},
+        Sniff: sniffClassicV2,
+        Capabilities: Capabilities{Entities: true, Player: true},
+        BlockTable: "0.30",
+        Reader: ReaderFunc(ReadClassicLevel),
+        NewWriter: func(options Options) (Writer, string, error) {
+            classicLevel := new(ClassicV2Level).InitWithDefaults()
+            // The version only picks the block table, MapBlocks downgrades the blocks
+            err := checkClassicVersion(options.TargetVersion)
+            if err != nil {
+                return nil, "", err
+            }
+            return classicLevel, "_v2.mine", nil
+        },
+    })
+    RegisterFormat(Format{
+        Name: "mcstructure",
+        Description: "Bedrock Edition structure",
+        Extensions: []string{".mcstructure"},
+        Sniff: sniffBedrockStructure,
+        Capabilities: Capabilities{Entities: true, MaxBlock: 49},
+        BlockTable: "0.30",
+        NewWriter: func(options Options) (Writer, string, error) {
+            return new(BedrockStructure).InitWithDefaults(), ".mcstructure", nil
+        },
+    })
+    RegisterFormat(Format{
+        Name: "pocket_level",
+        Description: "Pocket Edition 0.1 - 0.8 world folder",
+        Capabilities: Capabilities{Entities: true, MaxDimensions: [3]int16{256, 256, 128}, MaxBlock: 49},
+        BlockTable: "0.30",
+        NewWriter: func(options Options) (Writer, string, error) {
+            return &folderWriter{new(PocketLevel).InitWithDefaults()}, "_pe", nil
+        },
+    })
+    RegisterFormat(Format{
+        Name: "magicavoxel",
+        Description: "MagicaVoxel model",
+        Extensions: []string{".vox"},
+        Sniff: sniffMagic([]byte("VOX "), 0.95),
+        Capabilities: Capabilities{MaxBlock: classicMaxColoredBlock},
+        NewWriter: func(options Options) (Writer, string, error) {
+            return new(MagicaVoxel).InitWithDefaults(), ".vox", nil
+        },
+    })
+    RegisterFormat(Format{
+        Name: "wavefront_obj",
+        Description: "Wavefront OBJ mesh",
+        Extensions: []string{".obj"},
+        Sniff: sniffWavefrontOBJ,
+        Capabilities: Capabilities{MaxBlock: 49}, // Only 0.30 blocks have a tile in terrain.png
+        BlockTable: "0.30",
+        NewWriter: func(options Options) (Writer, string, error) {
+            wavefrontOBJ := new(WavefrontOBJ).InitWithDefaults()
+            wavefrontOBJ.Greedy = options.Greedy
+            wavefrontOBJ.TextureAtlas = ternary(options.Atlas != "", options.Atlas, "terrain.png")
+            return &objArchiveWriter{wavefrontOBJ}, ".obj", nil
+        },
+    })
+    RegisterFormat(Format{
+        Name: "gltf_binary",
+        Description: "Binary glTF 2.0 mesh",
+        Extensions: []string{".glb"},
+        Sniff: sniffMagic([]byte("glTF"), 0.95),
+        Capabilities: Capabilities{MaxBlock: 49},
+        BlockTable: "0.30",
+        NewWriter: func(options Options) (Writer, string, error) {
+            gltfBinary := new(GLTFBinary).InitWithDefaults()
+            gltfBinary.Greedy = options.Greedy
+
+            // Without a texture atlas the block colors are used as vertex colors
+            if options.AtlasData != nil {
+                gltfBinary.TextureAtlas = options.AtlasData
+            } else if options.Atlas != "" {
+                atlas, err := os.ReadFile(options.Atlas)
+                if err != nil {
+                    return nil, "", err
+                }
+                gltfBinary.TextureAtlas = atlas
+            }
+            return gltfBinary, ".glb", nil
+        },
+    })
+    RegisterFormat(Format{
+        Name: "stl_model",
+        Description: "STL model for 3D printing",
+        Extensions: []string{".stl"},
+        Sniff: sniffSTL,
+        Capabilities: Capabilities{MaxBlock: 49},
+        BlockTable: "0.30",
+        NewWriter: func(options Options) (Writer, string, error) {
+            stlModel := new(STLModel).InitWithDefaults()
+            stlModel.HollowThickness = options.Hollow
+            stlModel.BasePlate = options.BasePlate
+            stlModel.BlockSize = float32(options.BlockSize)
+            if options.Region == "" {
+                return stlModel, ".stl", nil
+            }
+
+            // FromLevel selects the whole world, so the region is picked once the level is known
+            var corners [6]int16
+            _, err := fmt.Sscanf(options.Region, "%d,%d,%d,%d,%d,%d", &corners[0], &corners[1], &corners[2], &corners[3], &corners[4], &corners[5])
+            if err != nil {
+                return nil, "", newError(ErrInvalidOption, "stl_model", err, "error: Region must be two corners written as \"x1,y1,z1,x2,y2,z2\". Got %s", options.Region)
+            }
+            return &stlRegionWriter{stlModel, [3]int16{corners[0], corners[1], corners[2]}, [3]int16{corners[3], corners[4], corners[5]}}, ".stl", nil
+        },
+    })
+    RegisterFormat(Format{
+        Name: "render",
+        Description: "PNG picture of the world (top, isometric or slices view)",
+        Extensions: []string{".png", ".gif"},
+        Sniff: sniffRender,
+        Capabilities: Capabilities{MaxBlock: classicMaxColoredBlock},
+        NewWriter: func(options Options) (Writer, string, error) {
+            switch options.View {
+            case "", "top":
+                return new(TopDownRender).InitWithDefaults(), ".png", nil
+            case "isometric":
+                isometricRender := new(IsometricRender).InitWithDefaults()
+                isometricRender.Angle = options.Angle
+                isometricRender.Cutaway = options.Cutaway
+                isometricRender.Scale = options.Scale
+                return isometricRender, "_isometric.png", nil
+            case "slices":
+                layerSliceRender := new(LayerSliceRender).InitWithDefaults()
+                layerSliceRender.Scale = options.Scale
+                if options.Animated {
+                    return layerSliceRender, "_slices.gif", nil
+                }
+                return &folderWriter{layerSliceRender}, "_slices", nil
+            default:
+                return nil, "", newError(ErrInvalidOption, "render", nil, "error: Unknown view %s. Expected one of top, isometric, slices", options.View)
+            }
+        },
+    })
+    RegisterFormat(Format{
+        Name: "json",
+        Description: "JSON dump of everything known about the world",
+        Extensions: []string{".json", ".ndjson"},
+        Sniff: sniffWorldDump,
+        Capabilities: Capabilities{Entities: true, Player: true},
+        NewWriter: func(options Options) (Writer, string, error) {
+            worldDump := new(WorldDump).InitWithDefaults()
+            worldDump.IncludeBlocks = options.IncludeBlocks
+            worldDump.NDJSON = options.NDJSON
+            return worldDump, ternary(options.NDJSON, ".ndjson", ".json"), nil
+        },
+    })
+    RegisterFormat(Format{
+        Name: "pre_classic",
+        Description: "Pre-classic (rd-132211 - 0.0.12a) level, 256x256x64 raw blocks",
+        Extensions: []string{".dat"},
+        Sniff: sniffPreClassic,
+        Capabilities: Capabilities{MaxDimensions: [3]int16{256, 256, 64}},
+        BlockTable: "pre_classic",
+        Reader: ReaderFunc(ReadClassicLevel),
+    })
+}
+
+// folderWriter is a Writer for formats written as a folder instead of a file, streamed as a zip archive of the folder
+type folderWriter struct {
+    writer interface {
+        FromLevel(level *Level)
+        WriteToFolder(foldername string) error
+        folderFiles() (map[string][]byte, error)
+    }
+}
+
+func (folder_writer *folderWriter) FromLevel(level *Level) {
+    folder_writer.writer.FromLevel(level)
+}
+
+func (folder_writer *folderWriter) WriteTo(w io.Writer) (int64, error) {
+    files, err := folder_writer.writer.folderFiles()
+    if err != nil {
+        return 0, err
+    }
+    return writeZip(w, files)
+}
+
+func (folder_writer *folderWriter) WriteToFile(filename string) error {
+    return folder_writer.writer.WriteToFolder(filename)
+}
+
+// objArchiveWriter streams the OBJ file together with its MTL file as a zip archive, the OBJ file is useless without it
+type objArchiveWriter struct {
+    *WavefrontOBJ
+}
+
+func (obj_archive_writer *objArchiveWriter) WriteTo(w io.Writer) (int64, error) {
+    obj, mtl, _, err := obj_archive_writer.meshBytes(obj_archive_writer.MaterialLibrary)
+    if err != nil {
+        return 0, err
+    }
+    name := strings.TrimSuffix(obj_archive_writer.MaterialLibrary, filepath.Ext(obj_archive_writer.MaterialLibrary))
+    return writeZip(w, map[string][]byte{name + ".obj": obj, obj_archive_writer.MaterialLibrary: mtl})
+}
+
+// stlRegionWriter picks the region to print after the model has been filled in
+type stlRegionWriter struct {
+    *STLModel
+    start [3]int16
+    end [3]int16
+}
+
+func (stl_region_writer *stlRegionWriter) FromLevel(level *Level) {
+    stl_region_writer.STLModel.FromLevel(level)
+    stl_region_writer.RegionStart = stl_region_writer.start
+    stl_region_writer.RegionEnd = stl_region_writer.end
+}
+
+// Checks that the target version is known, empty uses the format's default
+func checkClassicVersion(version string) error {
+    if _, ok := ClassicMaxBlock(version); version != "" && !ok {
+        return newError(ErrUnsupportedVersion, "", nil, "error: Unknown classic version %s. Expected one of %s", version, strings.Join(ClassicVersions(), ", "))
+    }
+    return nil
+}
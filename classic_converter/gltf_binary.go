@@ -0,0 +1,249 @@
+package classic_converter
+
+import (
+    "bytes"
+    "encoding/binary"
+    "encoding/json"
+    "io"
+    "math"
+)
+
+// glTF constants used by the exporter
+const (
+    gltfFloat = 5126
+    gltfUnsignedInt = 5125
+    gltfArrayBuffer = 34962
+    gltfElementArrayBuffer = 34963
+    gltfNearest = 9728
+)
+
+// GLTFBinary is a binary glTF 2.0 (.glb) mesh of every visible block face, the same mesh as WavefrontOBJ.
+// Opaque, transparent and liquid faces get a node each. Faces are textured with TextureAtlas when it is set,
+// otherwise they use the classic block colors as vertex colors.
+type GLTFBinary struct {
+    Width int16
+    Length int16
+    Height int16
+    Blocks []int8
+    Greedy bool
+    TextureAtlas []byte // Classic's terrain.png, embedded into the file
+    blockProgress
+}
+
+func (gltf_binary *GLTFBinary) InitWithDefaults() *GLTFBinary {
+    gltf_binary.Width = 256
+    gltf_binary.Length = 256
+    gltf_binary.Height = 64
+    gltf_binary.Blocks = make([]int8, 256*256*64)
+    gltf_binary.Greedy = false
+    gltf_binary.TextureAtlas = nil
+
+    return gltf_binary
+}
+
+func (gltf_binary *GLTFBinary) FromLevel(level *Level) {
+    gltf_binary.Width = level.Width
+    gltf_binary.Length = level.Length
+    gltf_binary.Height = level.Height
+    gltf_binary.Blocks = level.Blocks
+}
+
+func (gltf_binary *GLTFBinary) WriteTo(w io.Writer) (int64, error) {
+    groups, err := buildBlockMesh(gltf_binary.Blocks, int(gltf_binary.Width), int(gltf_binary.Length), int(gltf_binary.Height), gltf_binary.Greedy, gltf_binary.step)
+    if err != nil {
+        return 0, err
+    }
+    textured := len(gltf_binary.TextureAtlas) > 0
+
+    bin := new(bytes.Buffer)
+    bufferViews := []map[string]any{}
+    accessors := []map[string]any{}
+
+    // Every buffer view starts on a 4 byte boundary. Views are added in the middle of building the document,
+    // so the first error is kept in binErr and returned once it is built.
+    var binErr error
+    addBufferView := func(data any, target int) int {
+        for bin.Len() % 4 != 0 {
+            bin.WriteByte(0)
+        }
+        offset := bin.Len()
+        err := binary.Write(bin, binary.LittleEndian, data)
+        if err != nil && binErr == nil {
+            binErr = err
+        }
+
+        view := map[string]any{
+            "buffer": 0,
+            "byteOffset": offset,
+            "byteLength": bin.Len() - offset,
+        }
+        if target != 0 {
+            view["target"] = target
+        }
+        bufferViews = append(bufferViews, view)
+        return len(bufferViews) - 1
+    }
+    addAccessor := func(data any, target int, componentType int, count int, accessorType string) int {
+        accessor := map[string]any{
+            "bufferView": addBufferView(data, target),
+            "componentType": componentType,
+            "count": count,
+            "type": accessorType,
+        }
+        accessors = append(accessors, accessor)
+        return len(accessors) - 1
+    }
+
+    nodes := []map[string]any{}
+    meshes := []map[string]any{}
+    materials := []map[string]any{}
+    for group, faces := range groups {
+        if len(faces) == 0 {
+            continue
+        }
+
+        positions := make([][3]float32, 0, len(faces) * 4)
+        normals := make([][3]float32, 0, len(faces) * 4)
+        uvs := make([][2]float32, 0, len(faces) * 4)
+        colors := make([][4]float32, 0, len(faces) * 4)
+        indices := make([]uint32, 0, len(faces) * 6)
+
+        min := [3]float32{math.MaxFloat32, math.MaxFloat32, math.MaxFloat32}
+        max := [3]float32{-math.MaxFloat32, -math.MaxFloat32, -math.MaxFloat32}
+        for _, face := range faces {
+            blockColor := ClassicBlockColor(face.Block)
+
+            first := uint32(len(positions))
+            for k := 0; k < 4; k++ {
+                positions = append(positions, face.Positions[k])
+                normals = append(normals, face.Normal)
+                // glTF UVs start at the top left, OBJ UVs at the bottom left
+                uvs = append(uvs, [2]float32{face.UVs[k][0], 1 - face.UVs[k][1]})
+                colors = append(colors, [4]float32{float32(blockColor.R) / 255, float32(blockColor.G) / 255, float32(blockColor.B) / 255, float32(blockColor.A) / 255})
+
+                // Position accessors need the bounds of the mesh
+                for axis, value := range face.Positions[k] {
+                    if value < min[axis] {
+                        min[axis] = value
+                    }
+                    if value > max[axis] {
+                        max[axis] = value
+                    }
+                }
+            }
+            indices = append(indices, first, first + 1, first + 2, first, first + 2, first + 3)
+        }
+
+        attributes := map[string]any{
+            "POSITION": addAccessor(positions, gltfArrayBuffer, gltfFloat, len(positions), "VEC3"),
+            "NORMAL": addAccessor(normals, gltfArrayBuffer, gltfFloat, len(normals), "VEC3"),
+        }
+        accessors[attributes["POSITION"].(int)]["min"] = min
+        accessors[attributes["POSITION"].(int)]["max"] = max
+
+        material := map[string]any{
+            "name": meshGroupNames[group],
+            "pbrMetallicRoughness": map[string]any{
+                "metallicFactor": 0,
+                "roughnessFactor": 1,
+            },
+        }
+        if textured {
+            attributes["TEXCOORD_0"] = addAccessor(uvs, gltfArrayBuffer, gltfFloat, len(uvs), "VEC2")
+            material["pbrMetallicRoughness"].(map[string]any)["baseColorTexture"] = map[string]any{"index": 0}
+        } else {
+            attributes["COLOR_0"] = addAccessor(colors, gltfArrayBuffer, gltfFloat, len(colors), "VEC4")
+        }
+
+        // Textured leaves, glass and sprites are cut out, untextured ones only have their alpha to go by
+        switch {
+        case group == meshLiquid || (group == meshTransparent && !textured):
+            material["alphaMode"] = "BLEND"
+        case group == meshTransparent:
+            material["alphaMode"] = "MASK"
+        }
+        materials = append(materials, material)
+
+        meshes = append(meshes, map[string]any{
+            "name": meshGroupNames[group],
+            "primitives": []map[string]any{
+                {
+                    "attributes": attributes,
+                    "indices": addAccessor(indices, gltfElementArrayBuffer, gltfUnsignedInt, len(indices), "SCALAR"),
+                    "material": len(materials) - 1,
+                },
+            },
+        })
+        nodes = append(nodes, map[string]any{
+            "name": meshGroupNames[group],
+            "mesh": len(meshes) - 1,
+        })
+    }
+
+    sceneNodes := make([]int, len(nodes))
+    for i := range nodes {
+        sceneNodes[i] = i
+    }
+
+    document := map[string]any{
+        "asset": map[string]any{
+            "version": "2.0",
+            "generator": "Classic-Converter",
+        },
+        "scene": 0,
+        "scenes": []map[string]any{
+            {"nodes": sceneNodes},
+        },
+        "nodes": nodes,
+        "meshes": meshes,
+        "materials": materials,
+        "accessors": accessors,
+    }
+    if textured {
+        // Nearest filtering keeps the pixel art sharp
+        document["images"] = []map[string]any{
+            {"bufferView": addBufferView(gltf_binary.TextureAtlas, 0), "mimeType": "image/png"},
+        }
+        document["samplers"] = []map[string]any{
+            {"magFilter": gltfNearest, "minFilter": gltfNearest},
+        }
+        document["textures"] = []map[string]any{
+            {"source": 0, "sampler": 0},
+        }
+    }
+    if binErr != nil {
+        return 0, binErr
+    }
+    for bin.Len() % 4 != 0 {
+        bin.WriteByte(0)
+    }
+    document["bufferViews"] = bufferViews
+    document["buffers"] = []map[string]any{
+        {"byteLength": bin.Len()},
+    }
+
+    jsonChunk, err := json.Marshal(document)
+    if err != nil {
+        return 0, err
+    }
+    for len(jsonChunk) % 4 != 0 {
+        jsonChunk = append(jsonChunk, ' ')
+    }
+
+    // Header is the magic, the version and the total length, followed by the JSON and binary chunks
+    buffer := new(bytes.Buffer)
+    fields := []any{[]byte("glTF"), []uint32{2, uint32(12 + 8 + len(jsonChunk) + 8 + bin.Len())}, uint32(len(jsonChunk)), []byte("JSON"), jsonChunk, uint32(bin.Len()), []byte("BIN\x00"), bin.Bytes()}
+    for _, value := range fields {
+        err := binary.Write(buffer, binary.LittleEndian, value)
+        if err != nil {
+            return 0, err
+        }
+    }
+
+    n, err := w.Write(buffer.Bytes())
+    return int64(n), err
+}
+
+func (gltf_binary *GLTFBinary) WriteToFile(filename string) error {
+    return writeFile(filename, gltf_binary)
+}
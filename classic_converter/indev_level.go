@@ -1,10 +1,8 @@
 package classic_converter
 
 import (
-    "fmt"
-    "io/ioutil"
+    "io"
     "math/rand"
-    "os"
     "time"
 
     "github.com/BJTMastermind/go-nbt"
@@ -36,6 +34,8 @@ type IndevLevel struct {
     // Root
     Entities []nbt.Compound
     TileEntities []nbt.Compound
+    EmbedMetadata bool // Keeps everything else about the level in a ClassicConverter compound
+    metadata *LevelMetadata
 }
 
 func (indev_level *IndevLevel) InitWithDefaults() *IndevLevel {
@@ -60,35 +60,51 @@ func (indev_level *IndevLevel) InitWithDefaults() *IndevLevel {
     indev_level.Data = make([]int8, 256*256*64)
     indev_level.Entities = []nbt.Compound{}
     indev_level.TileEntities = []nbt.Compound{}
-
+    indev_level.EmbedMetadata = false
     return indev_level
 }
 
-func (indev_level *IndevLevel) FindSpawn() {
-    i := 0
-
-    var x int32
-    var y int32
-    var z int32
-    for ok := true; ok; ok = (y <= int32(indev_level.Height) / 2) { // do while loop
-        i++
-        x = rand.Int31n(int32(indev_level.Width) / 2) + int32(indev_level.Width) / 4
-        z = rand.Int31n(int32(indev_level.Length) / 2) + int32(indev_level.Length) / 4
-        y = indev_level.getHightestTile(x, z) + 1
-        if i == 10000 {
-            indev_level.Spawn[0] = int16(x)
-            indev_level.Spawn[1] = -100
-            indev_level.Spawn[2] = int16(z)
-            return
-        }
+// The player goes into the entity list like indev does it
+func (indev_level *IndevLevel) FromLevel(level *Level) {
+    indev_level.CreatedOn = level.CreatedOn
+    indev_level.Name = level.Name
+    indev_level.Author = level.Author
+    indev_level.Width = level.Width
+    indev_level.Length = level.Length
+    indev_level.Height = level.Height
+    indev_level.Blocks = level.Blocks
+    indev_level.Data = level.DataOrZero()
+
+    // Client default colors keep indev's own defaults
+    indev_level.SkyColor = ternary(level.SkyColor >= 0, level.SkyColor, indev_level.SkyColor)
+    indev_level.FogColor = ternary(level.FogColor >= 0, level.FogColor, indev_level.FogColor)
+    indev_level.CloudColor = ternary(level.CloudColor >= 0, level.CloudColor, indev_level.CloudColor)
+
+    compoundEntities := []nbt.Compound{}
+    for _, entity := range level.Mobs() {
+        compoundEntities = append(compoundEntities, ClassicEntity2Compound(entity, false))
+    }
+    if level.Player != nil {
+        compoundEntities = append(compoundEntities, ClassicPlayer2Compound(*level.Player))
+    }
+    indev_level.Entities = compoundEntities
+
+    if level.HasSpawn {
+        indev_level.Spawn = level.Spawn
+    } else {
+        indev_level.FindSpawn()
+    }
+
+    if indev_level.EmbedMetadata {
+        indev_level.metadata = level.Metadata()
     }
+}
 
-    indev_level.Spawn[0] = int16(x)
-    indev_level.Spawn[1] = int16(y)
-    indev_level.Spawn[2] = int16(z)
+func (indev_level *IndevLevel) FindSpawn() {
+    indev_level.Spawn = findSpawn(indev_level.Blocks, indev_level.Width, indev_level.Length, indev_level.Height)
 }
 
-func (indev_level *IndevLevel) WriteToFile(filename string) {
+func (indev_level *IndevLevel) WriteTo(w io.Writer) (int64, error) {
     root := nbt.NewCompoundTag("MinecraftLevel", map[string]nbt.Tag{
         "About": &nbt.Compound{
             Value: map[string]nbt.Tag{
@@ -179,29 +195,34 @@ func (indev_level *IndevLevel) WriteToFile(filename string) {
         },
     })
 
+    if indev_level.metadata != nil {
+        compound, err := metadataCompound(indev_level.metadata)
+        if err != nil {
+            return 0, err
+        }
+        root.Value["ClassicConverter"] = compound
+    }
+
     stream := nbt.NewStream(nbt.BigEndian)
 
     err := stream.WriteTag(root)
     if err != nil {
-        panic(err)
+        return 0, err
     }
 
     data, err := nbt.Compress(stream, nbt.CompressGZip, nbt.DefaultCompressionLevel)
     if err != nil {
-        panic(err)
+        return 0, err
     }
 
-    ioutil.WriteFile(filename, data, os.ModePerm)
+    n, err := w.Write(data)
+    return int64(n), err
+}
 
-    fmt.Printf("Generated %s\n", filename)
+func (indev_level *IndevLevel) WriteToFile(filename string) error {
+    return writeFile(filename, indev_level)
 }
 
 func (indev_level *IndevLevel) getHightestTile(x int32, z int32) int32 {
-    for y := int32(indev_level.Height) - 1; y >= 0; y-- {
-        index := (y * int32(indev_level.Length) + z) * int32(indev_level.Width) + x
-        if indev_level.Blocks[index] != 0 && indev_level.Blocks[index] != 8 && indev_level.Blocks[index] != 9 && indev_level.Blocks[index] != 10 && indev_level.Blocks[index] != 11 {
-            return y
-        }
-    }
-    return int32(indev_level.Height) / 2
+    return getHightestTile(indev_level.Blocks, indev_level.Width, indev_level.Length, indev_level.Height, x, z)
 }
@@ -0,0 +1,197 @@
+package classic_converter
+
+import (
+    "bytes"
+    "image"
+    "image/color"
+    "image/png"
+    "io"
+)
+
+// How bright each visible face of a block is drawn
+const (
+    isometricTopLight float32 = 1.0
+    isometricLeftLight float32 = 0.8
+    isometricRightLight float32 = 0.62
+)
+
+// IsometricRender is an isometric picture of the world, seen from one of its four corners.
+// Blocks above Cutaway are left out so the inside of builds can be seen.
+type IsometricRender struct {
+    Width int16
+    Length int16
+    Height int16
+    Blocks []int8
+    Angle int // 0 to 3, each one turns the world a quarter turn further
+    Cutaway int // Highest Y drawn, -1 draws everything
+    Scale int // Half the width of a block in pixels, rounded up to an even number
+}
+
+func (isometric_render *IsometricRender) InitWithDefaults() *IsometricRender {
+    isometric_render.Width = 256
+    isometric_render.Length = 256
+    isometric_render.Height = 64
+    isometric_render.Blocks = make([]int8, 256*256*64)
+    isometric_render.Angle = 0
+    isometric_render.Cutaway = -1
+    isometric_render.Scale = 4
+
+    return isometric_render
+}
+
+func (isometric_render *IsometricRender) FromLevel(level *Level) {
+    isometric_render.Width = level.Width
+    isometric_render.Length = level.Length
+    isometric_render.Height = level.Height
+    isometric_render.Blocks = level.Blocks
+}
+
+func (isometric_render *IsometricRender) Render() *image.RGBA {
+    width := int(isometric_render.Width)
+    length := int(isometric_render.Length)
+    height := int(isometric_render.Height)
+    // Odd sizes would leave gaps between rows of blocks
+    scale := clamp(isometric_render.Scale, 1, 64)
+    scale += scale % 2
+    angle := ((isometric_render.Angle % 4) + 4) % 4
+    if isometric_render.Cutaway >= 0 {
+        height = clamp(isometric_render.Cutaway + 1, 0, height)
+    }
+
+    // Turning the world a quarter turn swaps its width and length
+    viewWidth, viewLength := width, length
+    if angle % 2 == 1 {
+        viewWidth, viewLength = length, width
+    }
+    blockAt := func(x int, y int, z int) int8 {
+        if x < 0 || y < 0 || z < 0 || x >= viewWidth || y >= height || z >= viewLength {
+            return 0
+        }
+        switch angle {
+        case 1:
+            x, z = z, length - 1 - x
+        case 2:
+            x, z = width - 1 - x, length - 1 - z
+        case 3:
+            x, z = width - 1 - z, x
+        }
+        return isometric_render.Blocks[(y * length + z) * width + x]
+    }
+
+    // Blocks are drawn around the middle of their hexagon, x goes down and to the right, z down and to the left
+    img := image.NewRGBA(image.Rect(0, 0, (viewWidth + viewLength) * scale, (viewWidth + viewLength) * scale / 2 + height * scale + scale))
+    originX := viewLength * scale
+    originY := height * scale
+    top, left, right := isometricFaceMasks(scale)
+
+    // Going up a layer at a time and then towards the viewer draws everything in front last
+    for y := 0; y < height; y++ {
+        for z := 0; z < viewLength; z++ {
+            for x := 0; x < viewWidth; x++ {
+                block := blockAt(x, y, z)
+                if block == 0 {
+                    continue
+                }
+
+                screenX := originX + (x - z) * scale
+                screenY := originY + (x + z) * scale / 2 - y * scale
+                blockColor := ClassicBlockColor(block)
+
+                // Only the top, +z (left) and +x (right) faces face the viewer, blocks outside the world are air
+                faces := []struct {
+                    visible bool
+                    mask []image.Point
+                    light float32
+                }{
+                    {isFaceVisible(block, blockAt(x, y + 1, z)), top, isometricTopLight},
+                    {isFaceVisible(block, blockAt(x, y, z + 1)), left, isometricLeftLight},
+                    {isFaceVisible(block, blockAt(x + 1, y, z)), right, isometricRightLight},
+                }
+                for _, face := range faces {
+                    if !face.visible {
+                        continue
+                    }
+                    for _, point := range face.mask {
+                        blendPixel(img, screenX + point.X, screenY + point.Y, blockColor, face.light)
+                    }
+                }
+            }
+        }
+    }
+
+    return img
+}
+
+func (isometric_render *IsometricRender) WriteTo(w io.Writer) (int64, error) {
+    buffer := new(bytes.Buffer)
+
+    err := png.Encode(buffer, isometric_render.Render())
+    if err != nil {
+        return 0, err
+    }
+
+    n, err := w.Write(buffer.Bytes())
+    return int64(n), err
+}
+
+func (isometric_render *IsometricRender) WriteToFile(filename string) error {
+    return writeFile(filename, isometric_render)
+}
+
+// Works out which pixels around the middle of a block belong to its top, left and right faces
+func isometricFaceMasks(scale int) ([]image.Point, []image.Point, []image.Point) {
+    half := float32(scale) / 2
+    size := float32(scale)
+    topFace := [4][2]float32{{0, -size}, {size, -half}, {0, 0}, {-size, -half}}
+    leftFace := [4][2]float32{{-size, -half}, {0, 0}, {0, size}, {-size, half}}
+    rightFace := [4][2]float32{{0, 0}, {size, -half}, {size, half}, {0, size}}
+
+    var top, left, right []image.Point
+    for y := -scale; y < scale; y++ {
+        for x := -scale; x < scale; x++ {
+            center := [2]float32{float32(x) + 0.5, float32(y) + 0.5}
+            point := image.Point{x, y}
+            switch {
+            case insideQuad(center, topFace):
+                top = append(top, point)
+            case insideQuad(center, leftFace):
+                left = append(left, point)
+            case insideQuad(center, rightFace):
+                right = append(right, point)
+            }
+        }
+    }
+    return top, left, right
+}
+
+// Checks if the point is inside the convex quad, the corners can go either way around
+func insideQuad(point [2]float32, quad [4][2]float32) bool {
+    var positive, negative bool
+    for i := 0; i < 4; i++ {
+        a := quad[i]
+        b := quad[(i + 1) % 4]
+        cross := (b[0] - a[0]) * (point[1] - a[1]) - (b[1] - a[1]) * (point[0] - a[0])
+        positive = positive || cross > 0
+        negative = negative || cross < 0
+    }
+    return !(positive && negative)
+}
+
+// Draws a lit block color over the pixel, see-through blocks are blended with what is already there
+func blendPixel(img *image.RGBA, x int, y int, blockColor color.NRGBA, light float32) {
+    if !(image.Point{x, y}.In(img.Rect)) {
+        return
+    }
+
+    alpha := float32(blockColor.A) / 255
+    below := img.RGBAAt(x, y)
+    blend := func(value uint8, belowValue uint8) uint8 {
+        return uint8(clamp(float32(value) * light * alpha + float32(belowValue) * (1 - alpha), 0, 255))
+    }
+    img.SetRGBA(x, y, color.RGBA{
+        R: blend(blockColor.R, below.R),
+        G: blend(blockColor.G, below.G),
+        B: blend(blockColor.B, below.B),
+        A: uint8(clamp(float32(blockColor.A) + float32(below.A) * (1 - alpha), 0, 255)),
+    })
+}
@@ -0,0 +1,324 @@
+package classic_converter
+
+import (
+    "bytes"
+    "encoding/binary"
+    "sort"
+    "unicode/utf16"
+)
+
+// Java object serialization stream constants
+const (
+    javaStreamMagic uint16 = 0xaced
+    javaStreamVersion uint16 = 5
+    javaBaseWireHandle int32 = 0x7e0000
+
+    javaTCNull byte = 0x70
+    javaTCReference byte = 0x71
+    javaTCClassDesc byte = 0x72
+    javaTCObject byte = 0x73
+    javaTCString byte = 0x74
+    javaTCArray byte = 0x75
+    javaTCBlockData byte = 0x77
+    javaTCEndBlockData byte = 0x78
+
+    javaSCWriteMethod byte = 0x01
+    javaSCSerializable byte = 0x02
+)
+
+// javaField is a serializable field of a Java class.
+// TypeCode is one of the JVM type codes (B, C, D, F, I, J, S, Z, L or [), Signature is only used by L and [ fields.
+type javaField struct {
+    Name string
+    TypeCode byte
+    Signature string
+}
+
+// javaClass describes a serializable Java class the same way ObjectStreamClass does.
+type javaClass struct {
+    Name string
+    SerialVersionUID int64
+    Flags byte
+    Fields []javaField
+    Super *javaClass
+}
+
+// javaObject is an instance of a javaClass, field values are looked up by name for every class in the hierarchy.
+// Fields without a value get Java's default value. BlockData and Annotations are written after the fields of
+// classes using a custom writeObject method.
+type javaObject struct {
+    Class *javaClass
+    Values map[string]any
+    BlockData []byte
+    Annotations []any
+}
+
+// javaArray is a Java array of objects, primitive arrays are written straight from []int8 and []int32.
+type javaArray struct {
+    Class *javaClass
+    Values []any
+}
+
+var javaByteArrayClass = &javaClass{Name: "[B", SerialVersionUID: -5984413125824719648, Flags: javaSCSerializable}
+var javaIntArrayClass = &javaClass{Name: "[I", SerialVersionUID: 5600894804908749477, Flags: javaSCSerializable}
+var javaArrayListClass = newJavaClass("java.util.ArrayList", 8683452581122892189, javaSCSerializable | javaSCWriteMethod, nil, []javaField{
+    {Name: "size", TypeCode: 'I'},
+})
+
+var javaRandomClass = newJavaClass("java.util.Random", 3905348978240129619, javaSCSerializable | javaSCWriteMethod, nil, []javaField{
+    {Name: "haveNextNextGaussian", TypeCode: 'Z'},
+    {Name: "nextNextGaussian", TypeCode: 'D'},
+    {Name: "seed", TypeCode: 'J'},
+})
+
+// Creates a java.util.Random, its seed is stored as it is so only the low 48 bits are kept
+func newJavaRandom(seed int64) *javaObject {
+    return &javaObject{
+        Class: javaRandomClass,
+        Values: map[string]any{
+            "seed": (seed ^ 0x5deece66d) & (1 << 48 - 1),
+        },
+    }
+}
+
+// Creates a class with its fields sorted the same way as ObjectStreamClass, primitives first then by name
+func newJavaClass(name string, serialVersionUID int64, flags byte, super *javaClass, fields []javaField) *javaClass {
+    sorted := append([]javaField{}, fields...)
+    sort.SliceStable(sorted, func(i, j int) bool {
+        iPrimitive := sorted[i].TypeCode != 'L' && sorted[i].TypeCode != '['
+        jPrimitive := sorted[j].TypeCode != 'L' && sorted[j].TypeCode != '['
+        if iPrimitive != jPrimitive {
+            return iPrimitive
+        }
+        return sorted[i].Name < sorted[j].Name
+    })
+
+    return &javaClass{
+        Name: name,
+        SerialVersionUID: serialVersionUID,
+        Flags: flags,
+        Fields: sorted,
+        Super: super,
+    }
+}
+
+// Creates a java.util.ArrayList holding the given objects
+func newJavaArrayList(values []any) *javaObject {
+    capacity := make([]byte, 4)
+    binary.BigEndian.PutUint32(capacity, uint32(len(values)))
+
+    return &javaObject{
+        Class: javaArrayListClass,
+        Values: map[string]any{
+            "size": int32(len(values)),
+        },
+        BlockData: capacity,
+        Annotations: values,
+    }
+}
+
+// javaObjectWriter writes objects using the Java object serialization stream protocol.
+type javaObjectWriter struct {
+    buffer *bytes.Buffer
+    nextHandle int32
+    handles map[any]int32
+    strings map[string]int32
+}
+
+func newJavaObjectWriter() *javaObjectWriter {
+    writer := &javaObjectWriter{
+        buffer: new(bytes.Buffer),
+        nextHandle: javaBaseWireHandle,
+        handles: map[any]int32{},
+        strings: map[string]int32{},
+    }
+    writer.write(javaStreamMagic, javaStreamVersion)
+
+    return writer
+}
+
+func (writer *javaObjectWriter) Bytes() []byte {
+    return writer.buffer.Bytes()
+}
+
+func (writer *javaObjectWriter) write(values ...any) {
+    for _, value := range values {
+        binary.Write(writer.buffer, binary.BigEndian, value)
+    }
+}
+
+// Assigns the next handle to the given key, nil keys are for values that never get referenced again
+func (writer *javaObjectWriter) newHandle(key any) {
+    if key != nil {
+        writer.handles[key] = writer.nextHandle
+    }
+    writer.nextHandle++
+}
+
+// Writes a string the same way as DataOutputStream.writeUTF
+func (writer *javaObjectWriter) writeUTF(str string) {
+    var encoded []byte
+    for _, char := range utf16.Encode([]rune(str)) {
+        switch {
+        case char >= 0x01 && char <= 0x7f:
+            encoded = append(encoded, byte(char))
+        case char <= 0x7ff:
+            encoded = append(encoded, byte(0xc0 | (char >> 6)), byte(0x80 | (char & 0x3f)))
+        default:
+            encoded = append(encoded, byte(0xe0 | (char >> 12)), byte(0x80 | ((char >> 6) & 0x3f)), byte(0x80 | (char & 0x3f)))
+        }
+    }
+    writer.write(uint16(len(encoded)), encoded)
+}
+
+func (writer *javaObjectWriter) writeString(str string) {
+    if handle, ok := writer.strings[str]; ok {
+        writer.write(javaTCReference, handle)
+        return
+    }
+    writer.write(javaTCString)
+    writer.strings[str] = writer.nextHandle
+    writer.newHandle(nil)
+    writer.writeUTF(str)
+}
+
+func (writer *javaObjectWriter) writeClassDesc(class *javaClass) {
+    if class == nil {
+        writer.write(javaTCNull)
+        return
+    }
+    if handle, ok := writer.handles[class]; ok {
+        writer.write(javaTCReference, handle)
+        return
+    }
+
+    writer.write(javaTCClassDesc)
+    writer.writeUTF(class.Name)
+    writer.write(class.SerialVersionUID)
+    writer.newHandle(class)
+    writer.write(class.Flags, int16(len(class.Fields)))
+    for _, field := range class.Fields {
+        writer.write(field.TypeCode)
+        writer.writeUTF(field.Name)
+        if field.TypeCode == 'L' || field.TypeCode == '[' {
+            writer.writeString(field.Signature)
+        }
+    }
+    writer.write(javaTCEndBlockData)
+    writer.writeClassDesc(class.Super)
+}
+
+// Writes any supported value: nil, string, []int8, []int32, *javaArray or *javaObject
+func (writer *javaObjectWriter) WriteObject(value any) {
+    switch value := value.(type) {
+    case nil:
+        writer.write(javaTCNull)
+    case string:
+        writer.writeString(value)
+    case []int8:
+        writer.write(javaTCArray)
+        writer.writeClassDesc(javaByteArrayClass)
+        writer.newHandle(nil)
+        writer.write(int32(len(value)), value)
+    case []int32:
+        writer.write(javaTCArray)
+        writer.writeClassDesc(javaIntArrayClass)
+        writer.newHandle(nil)
+        writer.write(int32(len(value)), value)
+    case *javaArray:
+        if handle, ok := writer.handles[value]; ok {
+            writer.write(javaTCReference, handle)
+            return
+        }
+        writer.write(javaTCArray)
+        writer.writeClassDesc(value.Class)
+        writer.newHandle(value)
+        writer.write(int32(len(value.Values)))
+        for _, element := range value.Values {
+            writer.WriteObject(element)
+        }
+    case *javaObject:
+        if handle, ok := writer.handles[value]; ok {
+            writer.write(javaTCReference, handle)
+            return
+        }
+        writer.write(javaTCObject)
+        writer.writeClassDesc(value.Class)
+        writer.newHandle(value)
+        writer.writeClassData(value, value.Class)
+    }
+}
+
+// Writes the field values of every class in the hierarchy, starting at the top most super class
+func (writer *javaObjectWriter) writeClassData(object *javaObject, class *javaClass) {
+    if class.Super != nil {
+        writer.writeClassData(object, class.Super)
+    }
+
+    for _, field := range class.Fields {
+        value := object.Values[field.Name]
+        switch field.TypeCode {
+        case 'B':
+            writer.write(toInt8(value))
+        case 'C', 'S':
+            writer.write(toInt16(value))
+        case 'I':
+            writer.write(toInt32(value))
+        case 'J':
+            writer.write(toInt64(value))
+        case 'F':
+            writer.write(toFloat32(value))
+        case 'D':
+            writer.write(toFloat64(value))
+        case 'Z':
+            writer.write(toBool(value))
+        default:
+            writer.WriteObject(value)
+        }
+    }
+
+    if class.Flags & javaSCWriteMethod != 0 {
+        if len(object.BlockData) > 0 {
+            writer.write(javaTCBlockData, uint8(len(object.BlockData)), object.BlockData)
+        }
+        for _, annotation := range object.Annotations {
+            writer.WriteObject(annotation)
+        }
+        writer.write(javaTCEndBlockData)
+    }
+}
+
+func toInt8(value any) int8 {
+    number, _ := value.(int8)
+    return number
+}
+
+func toInt16(value any) int16 {
+    number, _ := value.(int16)
+    return number
+}
+
+func toInt32(value any) int32 {
+    number, _ := value.(int32)
+    return number
+}
+
+func toInt64(value any) int64 {
+    number, _ := value.(int64)
+    return number
+}
+
+func toFloat32(value any) float32 {
+    number, _ := value.(float32)
+    return number
+}
+
+func toFloat64(value any) float64 {
+    number, _ := value.(float64)
+    return number
+}
+
+func toBool(value any) bool {
+    boolean, _ := value.(bool)
+    return boolean
+}
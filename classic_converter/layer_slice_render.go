@@ -0,0 +1,266 @@
+package classic_converter
+
+import (
+    "bytes"
+    "fmt"
+    "image"
+    "image/color"
+    "image/color/palette"
+    "image/draw"
+    "image/gif"
+    "image/png"
+    "io"
+    "sort"
+
+    "golang.org/x/image/font"
+    "golang.org/x/image/font/basicfont"
+    "golang.org/x/image/math/fixed"
+)
+
+// Layout of a layer picture, the layer is drawn under a title with the legend to the right of it
+const (
+    sliceMargin = 8
+    sliceTitleHeight = 20
+    sliceLegendWidth = 190
+    sliceLegendRowHeight = 14
+)
+
+var (
+    sliceBackground = color.RGBA{245, 245, 240, 255}
+    sliceGridColor = color.RGBA{0, 0, 0, 40}
+    sliceChunkGridColor = color.RGBA{0, 0, 0, 90}
+    sliceTextColor = color.RGBA{30, 30, 30, 255}
+)
+
+// LayerSliceRender draws the world one Y layer at a time with a grid over it and a legend of the blocks in the layer.
+// Layers are written as a PNG each or as the frames of one animated GIF, empty layers are skipped.
+type LayerSliceRender struct {
+    Width int16
+    Length int16
+    Height int16
+    Blocks []int8
+    Scale int // Width of a block in pixels
+}
+
+func (layer_slice_render *LayerSliceRender) InitWithDefaults() *LayerSliceRender {
+    layer_slice_render.Width = 256
+    layer_slice_render.Length = 256
+    layer_slice_render.Height = 64
+    layer_slice_render.Blocks = make([]int8, 256*256*64)
+    layer_slice_render.Scale = 8
+
+    return layer_slice_render
+}
+
+func (layer_slice_render *LayerSliceRender) FromLevel(level *Level) {
+    layer_slice_render.Width = level.Width
+    layer_slice_render.Length = level.Length
+    layer_slice_render.Height = level.Height
+    layer_slice_render.Blocks = level.Blocks
+}
+
+// Returns every layer with at least one block in it, bottom first
+func (layer_slice_render *LayerSliceRender) Layers() []int {
+    layerSize := int(layer_slice_render.Width) * int(layer_slice_render.Length)
+
+    layers := []int{}
+    for y := 0; y < int(layer_slice_render.Height); y++ {
+        for _, block := range layer_slice_render.Blocks[y * layerSize:(y + 1) * layerSize] {
+            if block != 0 {
+                layers = append(layers, y)
+                break
+            }
+        }
+    }
+    return layers
+}
+
+// Every layer is made as tall as the legend of the whole world needs, so all of them are the same size
+func (layer_slice_render *LayerSliceRender) RenderLayer(y int) *image.RGBA {
+    return layer_slice_render.renderLayer(y, layer_slice_render.legendRows())
+}
+
+// Returns how many different blocks are in the world
+func (layer_slice_render *LayerSliceRender) legendRows() int {
+    var found [256]bool
+    rows := 0
+    for _, block := range layer_slice_render.Blocks {
+        if block != 0 && !found[uint8(block)] {
+            found[uint8(block)] = true
+            rows++
+        }
+    }
+    return rows
+}
+
+func (layer_slice_render *LayerSliceRender) renderLayer(y int, legendRows int) *image.RGBA {
+    width := int(layer_slice_render.Width)
+    length := int(layer_slice_render.Length)
+    scale := clamp(layer_slice_render.Scale, 1, 64)
+    blockAt := func(x int, y int, z int) int8 {
+        if y < 0 {
+            return 0
+        }
+        return layer_slice_render.Blocks[(y * length + z) * width + x]
+    }
+
+    counts := map[int8]int{}
+    for z := 0; z < length; z++ {
+        for x := 0; x < width; x++ {
+            if block := blockAt(x, y, z); block != 0 {
+                counts[block]++
+            }
+        }
+    }
+    legend := make([]int8, 0, len(counts))
+    for block := range counts {
+        legend = append(legend, block)
+    }
+    sort.Slice(legend, func(i, j int) bool {
+        if counts[legend[i]] != counts[legend[j]] {
+            return counts[legend[i]] > counts[legend[j]]
+        }
+        return legend[i] < legend[j]
+    })
+
+    mapWidth := width * scale
+    mapHeight := length * scale
+    contentHeight := mapHeight
+    if legendRows * sliceLegendRowHeight > contentHeight {
+        contentHeight = legendRows * sliceLegendRowHeight
+    }
+    img := image.NewRGBA(image.Rect(0, 0, sliceMargin * 3 + mapWidth + sliceLegendWidth, sliceMargin * 2 + sliceTitleHeight + contentHeight))
+    draw.Draw(img, img.Rect, &image.Uniform{sliceBackground}, image.Point{}, draw.Src)
+
+    drawSliceText(img, sliceMargin, sliceMargin + 13, fmt.Sprintf("Layer Y = %d", y))
+
+    // Air shows the layer under it faded out, so builds can be followed from one layer to the next
+    mapX := sliceMargin
+    mapY := sliceMargin + sliceTitleHeight
+    for z := 0; z < length; z++ {
+        for x := 0; x < width; x++ {
+            block := blockAt(x, y, z)
+            blockColor := ClassicBlockColor(block)
+            if block == 0 {
+                blockColor = ClassicBlockColor(blockAt(x, y - 1, z))
+                blockColor.A /= 4
+            }
+
+            cell := image.Rect(mapX + x * scale, mapY + z * scale, mapX + (x + 1) * scale, mapY + (z + 1) * scale)
+            draw.Draw(img, cell, &image.Uniform{blockColor}, image.Point{}, draw.Over)
+        }
+    }
+
+    // Grid lines go between blocks when there is room for them, every 16th line is darker
+    if scale >= 4 {
+        for x := 0; x <= width; x++ {
+            line := image.Rect(mapX + x * scale, mapY, mapX + x * scale + 1, mapY + mapHeight)
+            draw.Draw(img, line, &image.Uniform{ternary(x % 16 == 0, sliceChunkGridColor, sliceGridColor)}, image.Point{}, draw.Over)
+        }
+        for z := 0; z <= length; z++ {
+            line := image.Rect(mapX, mapY + z * scale, mapX + mapWidth, mapY + z * scale + 1)
+            draw.Draw(img, line, &image.Uniform{ternary(z % 16 == 0, sliceChunkGridColor, sliceGridColor)}, image.Point{}, draw.Over)
+        }
+    }
+
+    legendX := mapX + mapWidth + sliceMargin
+    for i, block := range legend {
+        rowY := mapY + i * sliceLegendRowHeight
+        swatch := image.Rect(legendX, rowY + 2, legendX + 10, rowY + 12)
+        draw.Draw(img, swatch, &image.Uniform{sliceTextColor}, image.Point{}, draw.Src)
+        draw.Draw(img, swatch.Inset(1), &image.Uniform{ClassicBlockColor(block)}, image.Point{}, draw.Over)
+        drawSliceText(img, legendX + 14, rowY + 11, fmt.Sprintf("%s (%d)", ClassicBlockName(block), counts[block]))
+    }
+
+    return img
+}
+
+// Writes a PNG for every layer into the given folder, creating it if needed
+func (layer_slice_render *LayerSliceRender) WriteToFolder(foldername string) error {
+    files, err := layer_slice_render.folderFiles()
+    if err != nil {
+        return fileError(foldername, err)
+    }
+    err = saveFolder(foldername, files)
+    if err != nil {
+        return err
+    }
+
+    logInfo("Generated %s (%d layers)", foldername, len(files))
+    return nil
+}
+
+// Returns a PNG for every layer, named after its Y
+func (layer_slice_render *LayerSliceRender) folderFiles() (map[string][]byte, error) {
+    files := map[string][]byte{}
+    legendRows := layer_slice_render.legendRows()
+    for _, y := range layer_slice_render.Layers() {
+        buffer := new(bytes.Buffer)
+
+        err := png.Encode(buffer, layer_slice_render.renderLayer(y, legendRows))
+        if err != nil {
+            return nil, err
+        }
+
+        files[fmt.Sprintf("layer_%03d.png", y)] = buffer.Bytes()
+    }
+    return files, nil
+}
+
+// Writes every layer as a frame of an animated GIF, going from the bottom up
+func (layer_slice_render *LayerSliceRender) WriteTo(w io.Writer) (int64, error) {
+    animation := &gif.GIF{}
+    bounds := image.Rectangle{}
+
+    legendRows := layer_slice_render.legendRows()
+    for _, y := range layer_slice_render.Layers() {
+        layer := layer_slice_render.renderLayer(y, legendRows)
+
+        bounds = layer.Rect
+        frame := image.NewPaletted(layer.Rect, palette.Plan9)
+        draw.Draw(frame, frame.Rect, layer, image.Point{}, draw.Src)
+
+        animation.Image = append(animation.Image, frame)
+        animation.Delay = append(animation.Delay, 50)
+    }
+    animation.Config = image.Config{
+        ColorModel: color.Palette(palette.Plan9),
+        Width: bounds.Dx(),
+        Height: bounds.Dy(),
+    }
+
+    buffer := new(bytes.Buffer)
+
+    err := gif.EncodeAll(buffer, animation)
+    if err != nil {
+        return 0, err
+    }
+    return buffer.WriteTo(w)
+}
+
+func (layer_slice_render *LayerSliceRender) WriteToFile(filename string) error {
+    buffer := new(bytes.Buffer)
+
+    _, err := layer_slice_render.WriteTo(buffer)
+    if err != nil {
+        return fileError(filename, err)
+    }
+
+    err = saveFile(filename, buffer.Bytes())
+    if err != nil {
+        return err
+    }
+
+    logInfo("Generated %s (%d layers)", filename, len(layer_slice_render.Layers()))
+    return nil
+}
+
+func drawSliceText(img *image.RGBA, x int, y int, text string) {
+    drawer := &font.Drawer{
+        Dst: img,
+        Src: &image.Uniform{sliceTextColor},
+        Face: basicfont.Face7x13,
+        Dot: fixed.P(x, y),
+    }
+    drawer.DrawString(text)
+}
@@ -0,0 +1,351 @@
+package classic_converter
+
+import (
+    "bytes"
+    "compress/gzip"
+    "encoding/binary"
+    "io"
+    "time"
+
+    "github.com/BJTMastermind/Go-MC-Classic-Parser"
+    "github.com/BJTMastermind/go-nbt"
+)
+
+// Level is a world in memory, independent of the format it was read from.
+// Every reader produces a Level and every writer is filled in from one with its FromLevel method.
+type Level struct {
+    // Metadata
+    Format string // Format the level was read from, like "classic_v2" or "schematic"
+    Name string
+    Author string
+    CreatedOn int64 // Milliseconds since the unix epoch, same as classic's createTime
+    // Dimensions, blocks are indexed as (y * Length + z) * Width + x
+    Width int16
+    Length int16
+    Height int16
+    Blocks []int8
+    Data []int8 // Block data values, nil when the format doesn't have any
+    // Spawn, writers look for a safe spawn themselves when HasSpawn is not set
+    HasSpawn bool
+    Spawn [3]int16
+    SpawnYaw float32 // Degrees
+    SpawnPitch float32 // Degrees
+    // Environment, -1 colors mean use the client default
+    SkyColor int32
+    FogColor int32
+    CloudColor int32
+    WaterLevel int32
+    CreativeMode bool
+    GrowTrees bool
+    // Entities as classic stores them, the player is in the list too with the "/char.png" texture
+    Entities []mc_classic_parser.ClassicEntity
+    Player *mc_classic_parser.ClassicPlayer // nil when the level has no player
+}
+
+func (level *Level) InitWithDefaults() *Level {
+    level.Format = ""
+    level.Name = "A Nice World"
+    level.Author = ""
+    level.CreatedOn = time.Now().UnixMilli()
+    level.Width = 256
+    level.Length = 256
+    level.Height = 64
+    level.Blocks = make([]int8, 256*256*64)
+    level.Data = nil
+    level.HasSpawn = false
+    level.SkyColor = -1
+    level.FogColor = -1
+    level.CloudColor = -1
+    level.WaterLevel = 32
+    level.CreativeMode = false
+    level.GrowTrees = false
+    level.Entities = []mc_classic_parser.ClassicEntity{}
+    level.Player = nil
+
+    return level
+}
+
+// Returns the entities without the player, which most formats store on its own or not at all
+func (level *Level) Mobs() []mc_classic_parser.ClassicEntity {
+    mobs := []mc_classic_parser.ClassicEntity{}
+    for _, entity := range level.Entities {
+        if entity.TextureName == "/char.png" {
+            continue
+        }
+        mobs = append(mobs, entity)
+    }
+    return mobs
+}
+
+// Logs every header field and what the level has in it, only shown when logging verbosely
+func (level *Level) logHeader() {
+    fields := map[string]any{
+        "format": level.Format,
+        "name": level.Name,
+        "author": level.Author,
+        "createdOn": level.CreatedOn,
+        "width": level.Width,
+        "length": level.Length,
+        "height": level.Height,
+        "waterLevel": level.WaterLevel,
+        "skyColor": level.SkyColor,
+        "fogColor": level.FogColor,
+        "cloudColor": level.CloudColor,
+        "creativeMode": level.CreativeMode,
+        "growTrees": level.GrowTrees,
+        "dataValues": level.Data != nil,
+        "entities": len(level.Mobs()),
+        "player": level.Player != nil,
+    }
+    if level.HasSpawn {
+        fields["spawn"] = level.Spawn
+        fields["spawnYaw"] = level.SpawnYaw
+    }
+    logDebug("Read level", fields)
+}
+
+// Returns the block data values, all zero when the level has none
+func (level *Level) DataOrZero() []int8 {
+    if len(level.Data) == len(level.Blocks) {
+        return level.Data
+    }
+    return make([]int8, len(level.Blocks))
+}
+
+// ReadClassicLevel decompresses the level and figures out if it is a pre-classic, classic version 1 or classic version 2 save.
+func ReadClassicLevel(reader io.Reader) (*Level, error) {
+    gzbytes, err := io.ReadAll(reader)
+    if err != nil {
+        return nil, err
+    }
+
+    // Check that given level is gzipped
+    if !bytes.HasPrefix(gzbytes, []byte{0x1f, 0x8b}) {
+        return nil, newError(ErrUnknownFormat, "", nil, "error: Not a GZIP file.")
+    }
+    gzReader, err := gzip.NewReader(bytes.NewReader(gzbytes))
+    if err != nil {
+        return nil, readError("", err)
+    }
+    defer gzReader.Close()
+
+    uncompressedBytes, err := io.ReadAll(gzReader)
+    if err != nil {
+        return nil, readError("", err)
+    }
+
+    level := new(Level).InitWithDefaults()
+
+    // Check if a classic world
+    logInfo("Figuring out what classic version the world is...")
+    if len(uncompressedBytes) < 5 || binary.BigEndian.Uint32(uncompressedBytes) != 0x271bb788 {
+        // Check if a pre classic world
+        if len(uncompressedBytes) != (256*256*64) {
+            return nil, newError(ErrUnknownFormat, "pre_classic", nil, "error: Not a vaild Minecraft Pre-Classic save, Byte array is not equal to 4,194,304 bytes.")
+        }
+
+        maxBlock := blockTables["pre_classic"].MaxBlock
+        for i := 0; i < (256*256*64); i++ {
+            if uncompressedBytes[i] > byte(maxBlock) {
+                return nil, newError(ErrUnknownFormat, "pre_classic", nil, "error: Not a vaild Minecraft Pre-Classic save, Byte array contains block IDs greater then %d.", maxBlock)
+            }
+        }
+
+        // Vaild Pre-Classic save
+        logInfo("Found pre-classic world format!")
+
+        level.Format = "pre_classic"
+        level.Blocks = ByteArray2Int8Array(uncompressedBytes)
+
+        return level, nil
+    }
+
+    version := uncompressedBytes[4]
+    levelReader := bytes.NewBuffer(uncompressedBytes[5:])
+
+    if version != 0x01 && version != 0x02 {
+        return nil, newError(ErrUnsupportedVersion, "", nil, "error: Not a supported classic format version. Got %d, Expected 1 or 2", version)
+    }
+
+    if version == 0x01 {
+        logInfo("Found classic version 1 world format!")
+
+        level.Format = "classic_v1"
+
+        // Cut short headers read as zeros, the level is rejected once the header is read
+        truncated := false
+        next := func(n int) []byte {
+            truncated = truncated || levelReader.Len() < n
+            part := make([]byte, n)
+            copy(part, levelReader.Next(n))
+            return part
+        }
+
+        worldNameLength := binary.BigEndian.Uint16(next(2))
+        level.Name = string(next(int(worldNameLength)))
+
+        creatorNameLength := binary.BigEndian.Uint16(next(2))
+        level.Author = string(next(int(creatorNameLength)))
+
+        level.CreatedOn = int64(binary.BigEndian.Uint64(next(8)))
+        level.Width = int16(binary.BigEndian.Uint16(next(2)))
+        level.Length = int16(binary.BigEndian.Uint16(next(2)))
+        level.Height = int16(binary.BigEndian.Uint16(next(2)))
+        level.WaterLevel = int32(level.Height) / 2
+        if truncated {
+            return nil, newError(ErrTruncated, "classic_v1", nil, "error: Not a vaild classic version 1 level, the header is cut short.")
+        }
+        level.Blocks = ByteArray2Int8Array(levelReader.Bytes())
+        if err := checkBlockCount("classic_v1", "classic version 1 level", level.Width, level.Length, level.Height, len(level.Blocks)); err != nil {
+            return nil, err
+        }
+    } else if version == 0x02 {
+        logInfo("Found classic version 2 world format!")
+
+        parser := new(mc_classic_parser.ClassicParser)
+
+        world, err := parser.ParseBytes(levelReader.Bytes())
+        if err != nil {
+            return nil, readError("classic_v2", err)
+        }
+
+        level.Format = "classic_v2"
+        level.SetClassicWorld(world)
+        if err := checkBlockCount("classic_v2", "classic version 2 level", level.Width, level.Length, level.Height, len(level.Blocks)); err != nil {
+            return nil, err
+        }
+    }
+    return level, nil
+}
+
+// Copies everything from a parsed classic version 2 world
+func (level *Level) SetClassicWorld(world *mc_classic_parser.ClassicWorld) {
+    level.Name = world.Name
+    level.Author = world.Creator
+    level.CreatedOn = world.CreateTime
+    level.Width = int16(world.Width)
+    level.Length = int16(world.Depth)
+    level.Height = int16(world.Height)
+    level.Blocks = world.Blocks
+    level.HasSpawn = true
+    level.Spawn = [3]int16{int16(world.XSpawn), int16(world.YSpawn), int16(world.ZSpawn)}
+    level.SpawnYaw = world.RotSpawn
+    level.SkyColor = world.SkyColor
+    level.FogColor = world.FogColor
+    level.CloudColor = world.CloudColor
+    level.WaterLevel = world.WaterLevel
+    level.CreativeMode = world.CreativeMode
+    level.GrowTrees = world.GrowTrees
+    level.Entities = world.Entities
+
+    // Levels written without a player have nothing to convert
+    if world.Player.Inventory != nil {
+        level.Player = &world.Player
+    }
+}
+
+func ReadIndevLevel(reader io.Reader) (*Level, error) {
+    stream, err := nbt.FromReader(reader, nbt.BigEndian)
+    if err != nil {
+        return nil, readError("indev_level", err)
+    }
+
+    tag, err := stream.ReadTag()
+    if err != nil {
+        return nil, readError("indev_level", err)
+    }
+    root, ok := tag.(*nbt.Compound)
+    if !ok {
+        return nil, newError(ErrUnknownFormat, "indev_level", nil, "error: Not a vaild Minecraft Indev level, root tag is not a compound.")
+    }
+
+    logInfo("Found indev level format!")
+
+    about, err := root.GetCompound("About")
+    if err != nil {
+        return nil, newError(ErrUnknownFormat, "indev_level", err, "error: Not a vaild Minecraft Indev level, it has no About compound.")
+    }
+    levelMap, err := root.GetCompound("Map")
+    if err != nil {
+        return nil, newError(ErrUnknownFormat, "indev_level", err, "error: Not a vaild Minecraft Indev level, it has no Map compound.")
+    }
+
+    level := new(Level).InitWithDefaults()
+    level.Format = "indev_level"
+    level.Name, _ = about.GetString("Name")
+    level.Author, _ = about.GetString("Author")
+    level.CreatedOn, _ = about.GetLong("CreatedOn")
+    level.Width, _ = levelMap.GetShort("Width")
+    level.Length, _ = levelMap.GetShort("Length")
+    level.Height, _ = levelMap.GetShort("Height")
+    level.WaterLevel = int32(level.Height) / 2
+
+    level.Blocks, err = levelMap.GetByteArray("Blocks")
+    if err != nil {
+        return nil, newError(ErrUnknownFormat, "indev_level", err, "error: Not a vaild Minecraft Indev level, it has no blocks.")
+    }
+    if err := checkBlockCount("indev_level", "Minecraft Indev level", level.Width, level.Length, level.Height, len(level.Blocks)); err != nil {
+        return nil, err
+    }
+    level.Data, _ = levelMap.GetByteArray("Data")
+    blockTables["indev"].importLevel(level)
+
+    if environment, err := root.GetCompound("Environment"); err == nil {
+        colors := map[string]*int32{"SkyColor": &level.SkyColor, "FogColor": &level.FogColor, "CloudColor": &level.CloudColor}
+        for name, color := range colors {
+            if value, err := environment.GetInt(name); err == nil {
+                *color = value
+            }
+        }
+    }
+
+
+    err = applyEmbeddedMetadata(root, level)
+    if err != nil {
+        return nil, err
+    }
+    return level, nil
+}
+
+// Schematics don't have a name, so the level's name is left empty for the caller to fill in
+func ReadSchematic(reader io.Reader) (*Level, error) {
+    stream, err := nbt.FromReader(reader, nbt.BigEndian)
+    if err != nil {
+        return nil, readError("schematic", err)
+    }
+
+    tag, err := stream.ReadTag()
+    if err != nil {
+        return nil, readError("schematic", err)
+    }
+    root, ok := tag.(*nbt.Compound)
+    if !ok {
+        return nil, newError(ErrUnknownFormat, "schematic", nil, "error: Not a vaild schematic, root tag is not a compound.")
+    }
+
+    logInfo("Found schematic format!")
+
+    level := new(Level).InitWithDefaults()
+    level.Format = "schematic"
+    level.Name = ""
+    level.Width, _ = root.GetShort("Width")
+    level.Length, _ = root.GetShort("Length")
+    level.Height, _ = root.GetShort("Height")
+    level.WaterLevel = int32(level.Height) / 2
+
+    level.Blocks, err = root.GetByteArray("Blocks")
+    if err != nil {
+        return nil, newError(ErrUnknownFormat, "schematic", err, "error: Not a vaild schematic, it has no blocks.")
+    }
+    if err := checkBlockCount("schematic", "schematic", level.Width, level.Length, level.Height, len(level.Blocks)); err != nil {
+        return nil, err
+    }
+    level.Data, _ = root.GetByteArray("Data")
+    blockTables["alpha"].importLevel(level)
+
+    err = applyEmbeddedMetadata(root, level)
+    if err != nil {
+        return nil, err
+    }
+    return level, nil
+}
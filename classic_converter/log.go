@@ -0,0 +1,159 @@
+package classic_converter
+
+import (
+    "encoding/json"
+    "fmt"
+    "io"
+    "os"
+    "sort"
+    "sync"
+    "time"
+)
+
+// LogLevel is how important a log message is, loggers leave out everything below their level
+type LogLevel int
+
+const (
+    LogDebug LogLevel = iota // Details like header fields and skipped entities
+    LogInfo // What the converter is doing, like the format it found and the files it wrote
+    LogWarn // Something in the world was lost or changed on the way
+    LogError // The conversion failed
+)
+
+func (log_level LogLevel) String() string {
+    switch log_level {
+    case LogDebug:
+        return "debug"
+    case LogInfo:
+        return "info"
+    case LogWarn:
+        return "warn"
+    default:
+        return "error"
+    }
+}
+
+// Logger gets every message the converter has for people. Fields are extra details for the message, nil when it has none.
+// Library callers can plug in their own with SetLogger, nothing is printed until they do.
+type Logger interface {
+    Log(level LogLevel, message string, fields map[string]any)
+}
+
+// LoggerFunc lets a plain function be used as a Logger
+type LoggerFunc func(level LogLevel, message string, fields map[string]any)
+
+func (logger_func LoggerFunc) Log(level LogLevel, message string, fields map[string]any) {
+    logger_func(level, message, fields)
+}
+
+var (
+    logger Logger // nil drops every message
+    loggerMutex sync.RWMutex
+)
+
+// SetLogger swaps the logger every message goes to, nil drops every message. It can be called while converting.
+func SetLogger(newLogger Logger) {
+    loggerMutex.Lock()
+    defer loggerMutex.Unlock()
+    logger = newLogger
+}
+
+func logMessage(level LogLevel, message string, fields map[string]any) {
+    loggerMutex.RLock()
+    current := logger
+    loggerMutex.RUnlock()
+
+    if current != nil {
+        current.Log(level, message, fields)
+    }
+}
+
+func logDebug(message string, fields map[string]any) {
+    logMessage(LogDebug, message, fields)
+}
+
+func logInfo(format string, args ...any) {
+    logMessage(LogInfo, fmt.Sprintf(format, args...), nil)
+}
+
+func logWarn(format string, args ...any) {
+    logMessage(LogWarn, fmt.Sprintf(format, args...), nil)
+}
+
+// TextLogger writes messages as plain lines, followed by their fields as key=value.
+// Warnings and errors go to ErrorWriter so they don't get mixed up with the rest.
+type TextLogger struct {
+    Writer io.Writer
+    ErrorWriter io.Writer
+    Level LogLevel
+    mutex sync.Mutex
+}
+
+func (text_logger *TextLogger) InitWithDefaults() *TextLogger {
+    text_logger.Writer = os.Stdout
+    text_logger.ErrorWriter = os.Stderr
+    text_logger.Level = LogInfo
+
+    return text_logger
+}
+
+func (text_logger *TextLogger) Log(level LogLevel, message string, fields map[string]any) {
+    if level < text_logger.Level {
+        return
+    }
+
+    line := message
+    for _, key := range sortedKeys(fields) {
+        line += fmt.Sprintf(" %s=%v", key, fields[key])
+    }
+
+    text_logger.mutex.Lock()
+    defer text_logger.mutex.Unlock()
+    fmt.Fprintln(ternary(level >= LogWarn, text_logger.ErrorWriter, text_logger.Writer), line)
+}
+
+// JSONLogger writes every message as a line of JSON with its time, level, message and fields
+type JSONLogger struct {
+    Writer io.Writer
+    Level LogLevel
+    mutex sync.Mutex
+}
+
+func (json_logger *JSONLogger) InitWithDefaults() *JSONLogger {
+    json_logger.Writer = os.Stdout
+    json_logger.Level = LogInfo
+
+    return json_logger
+}
+
+func (json_logger *JSONLogger) Log(level LogLevel, message string, fields map[string]any) {
+    if level < json_logger.Level {
+        return
+    }
+
+    line := map[string]any{}
+    for key, value := range fields {
+        line[key] = value
+    }
+    line["time"] = time.Now().Format(time.RFC3339Nano)
+    line["level"] = level.String()
+    line["message"] = message
+
+    data, err := json.Marshal(line)
+    if err != nil {
+        data, _ = json.Marshal(map[string]any{"level": level.String(), "message": message})
+    }
+
+    json_logger.mutex.Lock()
+    defer json_logger.mutex.Unlock()
+    json_logger.Writer.Write(append(data, '\n'))
+}
+
+func sortedKeys[T any](fields map[string]T) []string {
+    keys := make([]string, 0, len(fields))
+    for key := range fields {
+        keys = append(keys, key)
+    }
+    sort.Strings(keys)
+    return keys
+}
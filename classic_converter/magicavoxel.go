@@ -0,0 +1,216 @@
+package classic_converter
+
+import (
+    "bytes"
+    "encoding/binary"
+    "fmt"
+    "io"
+)
+
+// MagicaVoxel models can't be bigger than 256 voxels on any axis
+const voxModelSize = 256
+
+// MagicaVoxel is the MagicaVoxel (.vox) format, worlds bigger than one model are split into several models.
+// Block IDs are used as palette indices so every block keeps its own color.
+type MagicaVoxel struct {
+    Width int16
+    Length int16
+    Height int16
+    Blocks []int8
+}
+
+// voxModel is one part of the world, Offset is where its x, y and z start in the world
+type voxModel struct {
+    Offset [3]int
+    Size [3]int
+    Voxels []byte
+}
+
+func (magicavoxel *MagicaVoxel) InitWithDefaults() *MagicaVoxel {
+    magicavoxel.Width = 256
+    magicavoxel.Length = 256
+    magicavoxel.Height = 64
+    magicavoxel.Blocks = make([]int8, 256*256*64)
+
+    return magicavoxel
+}
+
+func (magicavoxel *MagicaVoxel) FromLevel(level *Level) {
+    magicavoxel.Width = level.Width
+    magicavoxel.Length = level.Length
+    magicavoxel.Height = level.Height
+    magicavoxel.Blocks = level.Blocks
+}
+
+func (magicavoxel *MagicaVoxel) WriteTo(w io.Writer) (int64, error) {
+    data, _, err := magicavoxel.voxBytes()
+    if err != nil {
+        return 0, err
+    }
+    n, err := w.Write(data)
+    return int64(n), err
+}
+
+func (magicavoxel *MagicaVoxel) WriteToFile(filename string) error {
+    data, modelCount, err := magicavoxel.voxBytes()
+    if err != nil {
+        return fileError(filename, err)
+    }
+
+    err = saveFile(filename, data)
+    if err != nil {
+        return err
+    }
+
+    logInfo("Generated %s (%d models)", filename, modelCount)
+    return nil
+}
+
+// Returns the whole file and how many models the world was split into
+func (magicavoxel *MagicaVoxel) voxBytes() ([]byte, int, error) {
+    width := int(magicavoxel.Width)
+    length := int(magicavoxel.Length)
+    height := int(magicavoxel.Height)
+
+    blocks := magicavoxel.Blocks
+
+    models := []voxModel{}
+    for offsetY := 0; offsetY < height; offsetY += voxModelSize {
+        for offsetZ := 0; offsetZ < length; offsetZ += voxModelSize {
+            for offsetX := 0; offsetX < width; offsetX += voxModelSize {
+                model := voxModel{
+                    Offset: [3]int{offsetX, offsetY, offsetZ},
+                    Size: [3]int{
+                        clamp(width - offsetX, 1, voxModelSize),
+                        clamp(height - offsetY, 1, voxModelSize),
+                        clamp(length - offsetZ, 1, voxModelSize),
+                    },
+                }
+
+                for y := 0; y < model.Size[1]; y++ {
+                    for z := 0; z < model.Size[2]; z++ {
+                        for x := 0; x < model.Size[0]; x++ {
+                            block := blocks[((offsetY + y) * length + offsetZ + z) * width + offsetX + x]
+                            if block == 0 {
+                                continue
+                            }
+                            // MagicaVoxel is z up, so classic's z becomes y and the height becomes z
+                            model.Voxels = append(model.Voxels, byte(x), byte(z), byte(y), byte(block))
+                        }
+                    }
+                }
+                models = append(models, model)
+            }
+        }
+    }
+
+    children := new(bytes.Buffer)
+    for _, model := range models {
+        err := writeVoxChunk(children, "SIZE", int32(model.Size[0]), int32(model.Size[2]), int32(model.Size[1]))
+        if err != nil {
+            return nil, 0, err
+        }
+        err = writeVoxChunk(children, "XYZI", int32(len(model.Voxels) / 4), model.Voxels)
+        if err != nil {
+            return nil, 0, err
+        }
+    }
+    if len(models) > 1 {
+        err := writeVoxSceneGraph(children, models)
+        if err != nil {
+            return nil, 0, err
+        }
+    }
+
+    // Palette index i is stored at i - 1, index 0 is always empty
+    palette := make([]byte, 256 * 4)
+    for i := 1; i < 256; i++ {
+        blockColor := ClassicBlockColor(int8(clamp(i, 1, int(classicMaxColoredBlock))))
+        copy(palette[(i - 1) * 4:], []byte{blockColor.R, blockColor.G, blockColor.B, blockColor.A})
+    }
+    err := writeVoxChunk(children, "RGBA", palette)
+    if err != nil {
+        return nil, 0, err
+    }
+
+    buffer := new(bytes.Buffer)
+    buffer.WriteString("VOX ")
+    fields := []any{int32(150), []byte("MAIN"), []int32{0, int32(children.Len())}, children.Bytes()}
+    for _, value := range fields {
+        err := binary.Write(buffer, binary.LittleEndian, value)
+        if err != nil {
+            return nil, 0, err
+        }
+    }
+
+    return buffer.Bytes(), len(models), nil
+}
+
+// Places every model at its spot in the world with a transform node each, all under one group
+func writeVoxSceneGraph(buffer *bytes.Buffer, models []voxModel) error {
+    // Node 0 is the root transform, node 1 the group and every model gets a transform and shape node after that
+    childIds := make([]int32, len(models))
+    for i := range models {
+        childIds[i] = int32(2 + i * 2)
+    }
+
+    err := writeVoxChunk(buffer, "nTRN", int32(0), voxDict(nil), int32(1), int32(-1), int32(-1), int32(1), voxDict(nil))
+    if err != nil {
+        return err
+    }
+    err = writeVoxChunk(buffer, "nGRP", int32(1), voxDict(nil), int32(len(models)), childIds)
+    if err != nil {
+        return err
+    }
+
+    for i, model := range models {
+        // Translations point at the center of a model
+        translation := fmt.Sprintf("%d %d %d",
+            model.Offset[0] + model.Size[0] / 2,
+            model.Offset[2] + model.Size[2] / 2,
+            model.Offset[1] + model.Size[1] / 2,
+        )
+
+        err := writeVoxChunk(buffer, "nTRN", childIds[i], voxDict(nil), childIds[i] + 1, int32(-1), int32(0), int32(1), voxDict(map[string]string{"_t": translation}))
+        if err != nil {
+            return err
+        }
+        err = writeVoxChunk(buffer, "nSHP", childIds[i] + 1, voxDict(nil), int32(1), int32(i), voxDict(nil))
+        if err != nil {
+            return err
+        }
+    }
+    return nil
+}
+
+// Chunks are an id, the content size, the size of any children (always 0 here) and the content
+func writeVoxChunk(buffer *bytes.Buffer, id string, values ...any) error {
+    content := new(bytes.Buffer)
+    for _, value := range values {
+        err := binary.Write(content, binary.LittleEndian, value)
+        if err != nil {
+            return err
+        }
+    }
+
+    buffer.WriteString(id)
+    err := binary.Write(buffer, binary.LittleEndian, []int32{int32(content.Len()), 0})
+    if err != nil {
+        return err
+    }
+    buffer.Write(content.Bytes())
+    return nil
+}
+
+// Dictionaries are a pair count followed by length prefixed keys and values
+func voxDict(dict map[string]string) []byte {
+    buffer := new(bytes.Buffer)
+    binary.Write(buffer, binary.LittleEndian, int32(len(dict)))
+    for key, value := range dict {
+        binary.Write(buffer, binary.LittleEndian, int32(len(key)))
+        buffer.WriteString(key)
+        binary.Write(buffer, binary.LittleEndian, int32(len(value)))
+        buffer.WriteString(value)
+    }
+    return buffer.Bytes()
+}
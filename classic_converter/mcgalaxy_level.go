@@ -0,0 +1,102 @@
+package classic_converter
+
+import (
+    "bytes"
+    "compress/gzip"
+    "encoding/binary"
+    "io"
+)
+
+// MCGalaxyLevel is the MCGalaxy (.lvl) level format, the one found in an MCGalaxy server's levels folder.
+type MCGalaxyLevel struct {
+    Width int16
+    Length int16
+    Height int16
+    Spawn [3]int16
+    SpawnYaw int8
+    SpawnPitch int8
+    VisitPermission int8
+    BuildPermission int8
+    Blocks []int8
+}
+
+func (mcgalaxy_level *MCGalaxyLevel) InitWithDefaults() *MCGalaxyLevel {
+    mcgalaxy_level.Width = 256
+    mcgalaxy_level.Length = 256
+    mcgalaxy_level.Height = 64
+    mcgalaxy_level.Spawn = [3]int16{128, 33, 128}
+    mcgalaxy_level.SpawnYaw = 0
+    mcgalaxy_level.SpawnPitch = 0
+    mcgalaxy_level.VisitPermission = 0 // Guest
+    mcgalaxy_level.BuildPermission = 0 // Guest
+    mcgalaxy_level.Blocks = make([]int8, 256*256*64)
+
+    return mcgalaxy_level
+}
+
+func (mcgalaxy_level *MCGalaxyLevel) FromLevel(level *Level) {
+    mcgalaxy_level.Width = level.Width
+    mcgalaxy_level.Length = level.Length
+    mcgalaxy_level.Height = level.Height
+    mcgalaxy_level.Blocks = level.Blocks
+
+    // Players spawn looking the way the classic player was looking
+    if level.HasSpawn {
+        mcgalaxy_level.Spawn = level.Spawn
+        mcgalaxy_level.SpawnYaw = Degrees2Byte(level.SpawnYaw)
+        mcgalaxy_level.SpawnPitch = Degrees2Byte(level.SpawnPitch)
+        if level.Player != nil {
+            mcgalaxy_level.SpawnYaw = Degrees2Byte(level.Player.YRot)
+            mcgalaxy_level.SpawnPitch = Degrees2Byte(level.Player.XRot)
+        }
+    } else {
+        mcgalaxy_level.FindSpawn()
+    }
+}
+
+func (mcgalaxy_level *MCGalaxyLevel) FindSpawn() {
+    mcgalaxy_level.Spawn = findSafeSpawn(mcgalaxy_level.Blocks, mcgalaxy_level.Width, mcgalaxy_level.Length, mcgalaxy_level.Height)
+}
+
+func (mcgalaxy_level *MCGalaxyLevel) WriteTo(w io.Writer) (int64, error) {
+    buffer := new(bytes.Buffer)
+    gzWriter := gzip.NewWriter(buffer)
+
+    // Header is 18 bytes, all shorts are little endian
+    header := []any{
+        uint16(1874), // Magic
+        mcgalaxy_level.Width,
+        mcgalaxy_level.Length,
+        mcgalaxy_level.Height,
+        mcgalaxy_level.Spawn[0],
+        mcgalaxy_level.Spawn[2],
+        mcgalaxy_level.Spawn[1],
+        mcgalaxy_level.SpawnYaw,
+        mcgalaxy_level.SpawnPitch,
+        mcgalaxy_level.VisitPermission,
+        mcgalaxy_level.BuildPermission,
+    }
+    for _, value := range header {
+        err := binary.Write(gzWriter, binary.LittleEndian, value)
+        if err != nil {
+            return 0, err
+        }
+    }
+
+    err := binary.Write(gzWriter, binary.LittleEndian, mcgalaxy_level.Blocks)
+    if err != nil {
+        return 0, err
+    }
+
+    err = gzWriter.Close()
+    if err != nil {
+        return 0, err
+    }
+
+    n, err := w.Write(buffer.Bytes())
+    return int64(n), err
+}
+
+func (mcgalaxy_level *MCGalaxyLevel) WriteToFile(filename string) error {
+    return writeFile(filename, mcgalaxy_level)
+}
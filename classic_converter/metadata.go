@@ -0,0 +1,206 @@
+package classic_converter
+
+import (
+    "encoding/json"
+    "errors"
+    "io"
+    "os"
+    "strings"
+
+    "github.com/BJTMastermind/Go-MC-Classic-Parser"
+    "github.com/BJTMastermind/go-nbt"
+)
+
+// How metadata is kept next to the output, set with Options.Metadata
+const (
+    MetadataNone = ""
+    MetadataSidecar = "sidecar" // A JSON file named after the output with MetadataFilename
+    MetadataEmbed = "embed" // A ClassicConverter compound in the output's root, only NBT formats can have one
+)
+
+// LevelMetadata is everything about a level except its blocks, so formats that can't hold all of it lose nothing.
+// Readers put it back onto the level they read, as long as the dimensions still match.
+type LevelMetadata struct {
+    Version int `json:"version"`
+    SourceFormat string `json:"sourceFormat"`
+    Width int16 `json:"width"`
+    Length int16 `json:"length"`
+    Height int16 `json:"height"`
+    Name string `json:"name"`
+    Author string `json:"author"`
+    CreatedOn int64 `json:"createdOn"`
+    HasSpawn bool `json:"hasSpawn"`
+    Spawn [3]int16 `json:"spawn"`
+    SpawnYaw float32 `json:"spawnYaw"`
+    SpawnPitch float32 `json:"spawnPitch"`
+    SkyColor int32 `json:"skyColor"`
+    FogColor int32 `json:"fogColor"`
+    CloudColor int32 `json:"cloudColor"`
+    WaterLevel int32 `json:"waterLevel"`
+    CreativeMode bool `json:"creativeMode"`
+    GrowTrees bool `json:"growTrees"`
+    Entities []mc_classic_parser.ClassicEntity `json:"entities"`
+    Player *mc_classic_parser.ClassicPlayer `json:"player"`
+}
+
+// Metadata returns everything about the level except its blocks
+func (level *Level) Metadata() *LevelMetadata {
+    return &LevelMetadata{
+        Version: 1,
+        SourceFormat: level.Format,
+        Width: level.Width,
+        Length: level.Length,
+        Height: level.Height,
+        Name: level.Name,
+        Author: level.Author,
+        CreatedOn: level.CreatedOn,
+        HasSpawn: level.HasSpawn,
+        Spawn: level.Spawn,
+        SpawnYaw: level.SpawnYaw,
+        SpawnPitch: level.SpawnPitch,
+        SkyColor: level.SkyColor,
+        FogColor: level.FogColor,
+        CloudColor: level.CloudColor,
+        WaterLevel: level.WaterLevel,
+        CreativeMode: level.CreativeMode,
+        GrowTrees: level.GrowTrees,
+        Entities: level.Entities,
+        Player: level.Player,
+    }
+}
+
+// ApplyMetadata puts the metadata back onto the level. Metadata of a world with other dimensions is left out, it belongs to something else.
+func (level *Level) ApplyMetadata(metadata *LevelMetadata) bool {
+    if metadata.Width != level.Width || metadata.Length != level.Length || metadata.Height != level.Height {
+        logWarn("Metadata is for a %dx%dx%d world but the world is %dx%dx%d, not using it", metadata.Width, metadata.Height, metadata.Length, level.Width, level.Height, level.Length)
+        return false
+    }
+
+    level.Name = metadata.Name
+    level.Author = metadata.Author
+    level.CreatedOn = metadata.CreatedOn
+    level.HasSpawn = metadata.HasSpawn
+    level.Spawn = metadata.Spawn
+    level.SpawnYaw = metadata.SpawnYaw
+    level.SpawnPitch = metadata.SpawnPitch
+    level.SkyColor = metadata.SkyColor
+    level.FogColor = metadata.FogColor
+    level.CloudColor = metadata.CloudColor
+    level.WaterLevel = metadata.WaterLevel
+    level.CreativeMode = metadata.CreativeMode
+    level.GrowTrees = metadata.GrowTrees
+    level.Entities = metadata.Entities
+    level.Player = metadata.Player
+    if level.Entities == nil {
+        level.Entities = []mc_classic_parser.ClassicEntity{}
+    }
+    return true
+}
+
+func (level_metadata *LevelMetadata) WriteTo(w io.Writer) (int64, error) {
+    data, err := json.MarshalIndent(level_metadata, "", "  ")
+    if err != nil {
+        return 0, err
+    }
+    n, err := w.Write(append(data, '\n'))
+    return int64(n), err
+}
+
+func (level_metadata *LevelMetadata) WriteToFile(filename string) error {
+    return writeFile(filename, level_metadata)
+}
+
+// ReadMetadata reads metadata written by LevelMetadata's WriteTo
+func ReadMetadata(reader io.Reader) (*LevelMetadata, error) {
+    metadata := new(LevelMetadata)
+    err := json.NewDecoder(reader).Decode(metadata)
+    if err != nil {
+        return nil, newError(ErrUnknownFormat, "", err, "error: Not vaild level metadata, %s.", err)
+    }
+    if metadata.Player != nil {
+        metadata.Player.Inventory = jsonInventory2Classic(metadata.Player.Inventory)
+    }
+    return metadata, nil
+}
+
+// MetadataFilename is where the metadata sidecar of the file goes
+func MetadataFilename(filename string) string {
+    return filename + ".meta.json"
+}
+
+// Reads the sidecar next to the file when there is one
+func readMetadataSidecar(filename string) (*LevelMetadata, error) {
+    file, err := os.Open(MetadataFilename(filename))
+    if errors.Is(err, os.ErrNotExist) {
+        return nil, nil
+    }
+    if err != nil {
+        return nil, err
+    }
+    defer file.Close()
+
+    return ReadMetadata(file)
+}
+
+// Embedded metadata is kept as JSON in a string tag, so it holds exactly what a sidecar would
+func metadataCompound(metadata *LevelMetadata) (*nbt.Compound, error) {
+    data, err := json.Marshal(metadata)
+    if err != nil {
+        return nil, err
+    }
+    return &nbt.Compound{
+        Value: map[string]nbt.Tag{
+            "Version": &nbt.Int{
+                Value: int32(metadata.Version),
+            },
+            "Metadata": &nbt.String{
+                Value: string(data),
+            },
+        },
+    }, nil
+}
+
+// Puts embedded metadata back onto the level, files without any are left as they are
+func applyEmbeddedMetadata(root *nbt.Compound, level *Level) error {
+    compound, err := root.GetCompound("ClassicConverter")
+    if err != nil {
+        return nil
+    }
+    data, err := compound.GetString("Metadata")
+    if err != nil {
+        return nil
+    }
+
+    metadata, err := ReadMetadata(strings.NewReader(data))
+    if err != nil {
+        return err
+    }
+    if level.ApplyMetadata(metadata) {
+        logInfo("Restored the metadata of the %s it was converted from", metadata.SourceFormat)
+    }
+    return nil
+}
+
+// JSON gives back the inventory's arrays as []any of float64, the parser has them as []int32 and the selected slot as int32
+func jsonInventory2Classic(inventory map[string]any) map[string]any {
+    if inventory == nil {
+        return nil
+    }
+    classic := map[string]any{}
+    for key, value := range inventory {
+        switch value := value.(type) {
+        case []any:
+            numbers := make([]int32, len(value))
+            for i, number := range value {
+                float, _ := number.(float64)
+                numbers[i] = int32(float)
+            }
+            classic[key] = numbers
+        case float64:
+            classic[key] = int32(value)
+        default:
+            classic[key] = value
+        }
+    }
+    return classic
+}
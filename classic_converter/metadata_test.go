@@ -0,0 +1,88 @@
+package classic_converter
+
+import (
+    "os"
+    "path/filepath"
+    "reflect"
+    "testing"
+)
+
+func TestMetadataRoundTrip(t *testing.T) {
+    tests := []struct {
+        format string
+        metadata string
+    }{
+        {"schematic", MetadataEmbed},
+        {"schematic", MetadataSidecar},
+        {"indev_level", MetadataEmbed},
+        {"indev_level", MetadataSidecar},
+    }
+
+    for _, test := range tests {
+        t.Run(test.format + " " + test.metadata, func(t *testing.T) {
+            expected := testClassicV2Level()
+            expected.Format = "classic_v2"
+            expected.SkyColor = 0x99ccff
+            expected.FogColor = 0x336699
+            expected.CloudColor = 0xeeeeee
+
+            format, _ := FormatByName(test.format)
+            options := *new(Options).InitWithDefaults()
+            options.Format = test.format
+            options.Metadata = test.metadata
+            writer, extension, err := format.NewWriter(options)
+            if err != nil {
+                t.Fatal(err)
+            }
+            mapped, err := format.MapBlocks(expected, options)
+            if err != nil {
+                t.Fatal(err)
+            }
+            writer.FromLevel(mapped)
+
+            filename := filepath.Join(t.TempDir(), "round_trip" + extension)
+            err = writer.WriteToFile(filename)
+            if err != nil {
+                t.Fatal(err)
+            }
+            if test.metadata == MetadataSidecar {
+                err = expected.Metadata().WriteToFile(MetadataFilename(filename))
+                if err != nil {
+                    t.Fatal(err)
+                }
+            } else if _, err := os.Stat(MetadataFilename(filename)); err == nil {
+                t.Fatalf("embedding the metadata wrote a sidecar")
+            }
+
+            level, err := ReadLevel(filename)
+            if err != nil {
+                t.Fatal(err)
+            }
+            if level.Name != expected.Name || level.Author != expected.Author || level.CreatedOn != expected.CreatedOn {
+                t.Errorf("header is %q by %q at %d, expected %q by %q at %d", level.Name, level.Author, level.CreatedOn, expected.Name, expected.Author, expected.CreatedOn)
+            }
+            if level.SkyColor != expected.SkyColor || level.FogColor != expected.FogColor || level.CloudColor != expected.CloudColor {
+                t.Errorf("colors are %06x %06x %06x, expected %06x %06x %06x", level.SkyColor, level.FogColor, level.CloudColor, expected.SkyColor, expected.FogColor, expected.CloudColor)
+            }
+            if !level.HasSpawn || level.Spawn != expected.Spawn || level.WaterLevel != expected.WaterLevel {
+                t.Errorf("spawn %v water level %d, expected %v and %d", level.Spawn, level.WaterLevel, expected.Spawn, expected.WaterLevel)
+            }
+            if len(level.Mobs()) != len(expected.Mobs()) {
+                t.Errorf("%d mobs came back, expected %d", len(level.Mobs()), len(expected.Mobs()))
+            }
+
+            if level.Player == nil {
+                t.Fatalf("player is missing")
+            }
+            if level.Player.X != expected.Player.X || level.Player.Y != expected.Player.Y || level.Player.Z != expected.Player.Z {
+                t.Errorf("player is at %v, %v, %v, expected %v, %v, %v", level.Player.X, level.Player.Y, level.Player.Z, expected.Player.X, expected.Player.Y, expected.Player.Z)
+            }
+            if level.Player.Arrows != expected.Player.Arrows || level.Player.Score != expected.Player.Score {
+                t.Errorf("player has %d arrows and %d score, expected %d and %d", level.Player.Arrows, level.Player.Score, expected.Player.Arrows, expected.Player.Score)
+            }
+            if !reflect.DeepEqual(level.Player.Inventory, expected.Player.Inventory) {
+                t.Errorf("player inventory is %v, expected %v", level.Player.Inventory, expected.Player.Inventory)
+            }
+        })
+    }
+}
@@ -0,0 +1,362 @@
+package classic_converter
+
+import (
+    "encoding/binary"
+    "io"
+    "time"
+
+    "github.com/BJTMastermind/Go-MC-Classic-Parser"
+    "github.com/BJTMastermind/go-nbt"
+)
+
+// Pocket Edition worlds before 0.9 are always 256x128x256, split into 16x16 chunks
+const (
+    pocketWorldSize = 256
+    pocketWorldHeight = 128
+    pocketChunkSectors = 21
+    pocketSectorSize = 4096
+)
+
+// Pocket Edition only has one cloth block, classic's cloth colors are stored as its data value
+var classicCloth2PocketWool = map[int8]int8{
+    21: 14, // Red
+    22: 1, // Orange
+    23: 4, // Yellow
+    24: 5, // Lime
+    25: 13, // Green
+    26: 9, // Aqua Green -> Cyan
+    27: 3, // Cyan -> Light Blue
+    28: 11, // Blue
+    29: 10, // Purple
+    30: 11, // Indigo -> Blue
+    31: 10, // Violet -> Purple
+    32: 2, // Magenta
+    33: 6, // Pink
+    34: 15, // Black
+    35: 7, // Gray
+    36: 0, // White
+}
+
+// PocketLevel is the legacy Pocket Edition 0.1 - 0.8 world folder (level.dat, chunks.dat and entities.dat).
+type PocketLevel struct {
+    Name string
+    GameType int32
+    LastPlayed int64
+    RandomSeed int64
+    Width int16
+    Length int16
+    Height int16
+    Spawn [3]int16
+    Blocks []int8
+    Entities []nbt.Compound
+}
+
+func (pocket_level *PocketLevel) InitWithDefaults() *PocketLevel {
+    pocket_level.Name = "A Nice World"
+    pocket_level.GameType = 1 // Creative
+    pocket_level.LastPlayed = time.Now().Unix()
+    pocket_level.RandomSeed = time.Now().UnixNano()
+    pocket_level.Width = 256
+    pocket_level.Length = 256
+    pocket_level.Height = 64
+    pocket_level.Spawn = [3]int16{128, 33, 128}
+    pocket_level.Blocks = make([]int8, 256*256*64)
+    pocket_level.Entities = []nbt.Compound{}
+
+    return pocket_level
+}
+
+// Pocket edition worlds are at most 256x128x256 blocks, anything outside of that is cut off
+func (pocket_level *PocketLevel) FromLevel(level *Level) {
+    if level.Width > 256 || level.Length > 256 || level.Height > 128 {
+        logWarn("World is %dx%dx%d, only the first 256x128x256 blocks fit in a pocket edition world", level.Width, level.Height, level.Length)
+    }
+
+    pocket_level.Name = level.Name
+    pocket_level.Width = level.Width
+    pocket_level.Length = level.Length
+    pocket_level.Height = level.Height
+    pocket_level.Blocks = level.Blocks
+    pocket_level.GameType = ternary[int32](level.CreativeMode, 1, 0)
+
+    compoundEntities := []nbt.Compound{}
+    for _, entity := range level.Mobs() {
+        compoundEntities = append(compoundEntities, ClassicEntity2PocketCompound(entity))
+    }
+    pocket_level.Entities = compoundEntities
+
+    if level.HasSpawn {
+        pocket_level.Spawn = level.Spawn
+    } else {
+        pocket_level.FindSpawn()
+    }
+}
+
+func (pocket_level *PocketLevel) FindSpawn() {
+    pocket_level.Spawn = findSafeSpawn(pocket_level.Blocks, pocket_level.Width, pocket_level.Length, pocket_level.Height)
+}
+
+// Writes level.dat, chunks.dat and entities.dat into the given folder, creating it if needed
+func (pocket_level *PocketLevel) WriteToFolder(foldername string) error {
+    files, err := pocket_level.folderFiles()
+    if err != nil {
+        return newError(ErrWriteFailed, "pocket_level", err, "error: Could not write the world into %s, %s.", foldername, err)
+    }
+    err = saveFolder(foldername, files)
+    if err != nil {
+        return err
+    }
+
+    logInfo("Generated %s", foldername)
+    return nil
+}
+
+// Writes the world folder as a zip archive
+func (pocket_level *PocketLevel) WriteTo(w io.Writer) (int64, error) {
+    files, err := pocket_level.folderFiles()
+    if err != nil {
+        return 0, err
+    }
+    return writeZip(w, files)
+}
+
+func (pocket_level *PocketLevel) folderFiles() (map[string][]byte, error) {
+    level, err := pocket_level.levelBytes()
+    if err != nil {
+        return nil, err
+    }
+    entities, err := pocket_level.entitiesBytes()
+    if err != nil {
+        return nil, err
+    }
+    return map[string][]byte{
+        "chunks.dat": pocket_level.chunksBytes(),
+        "level.dat": level,
+        "entities.dat": entities,
+    }, nil
+}
+
+func (pocket_level *PocketLevel) levelBytes() ([]byte, error) {
+    root := nbt.NewCompoundTag("", map[string]nbt.Tag{
+        "GameType": &nbt.Int{
+            Value: pocket_level.GameType,
+        },
+        "LastPlayed": &nbt.Long{
+            Value: pocket_level.LastPlayed,
+        },
+        "LevelName": &nbt.String{
+            Value: pocket_level.Name,
+        },
+        "Platform": &nbt.Int{
+            Value: 2,
+        },
+        "RandomSeed": &nbt.Long{
+            Value: pocket_level.RandomSeed,
+        },
+        "SizeOnDisk": &nbt.Long{
+            Value: int64(pocketSectorSize + pocketWorldSize / 16 * pocketWorldSize / 16 * pocketChunkSectors * pocketSectorSize),
+        },
+        "SpawnX": &nbt.Int{
+            Value: int32(pocket_level.Spawn[0]),
+        },
+        "SpawnY": &nbt.Int{
+            Value: int32(pocket_level.Spawn[1]),
+        },
+        "SpawnZ": &nbt.Int{
+            Value: int32(pocket_level.Spawn[2]),
+        },
+        "StorageVersion": &nbt.Int{
+            Value: 3,
+        },
+        "Time": &nbt.Long{
+            Value: 0,
+        },
+        "spawnMobs": &nbt.Byte{
+            Value: 0,
+        },
+    })
+
+    return pocketNBTFile(root, []byte{3, 0, 0, 0})
+}
+
+func (pocket_level *PocketLevel) entitiesBytes() ([]byte, error) {
+    root := nbt.NewCompoundTag("", map[string]nbt.Tag{
+        "Entities": &nbt.List{
+            Value: compoundArrayToTagArray(pocket_level.Entities),
+            ListType: ternary[int8](len(pocket_level.Entities) == 0, nbt.IDTagEnd, nbt.IDTagCompound),
+        },
+        "TileEntities": &nbt.List{
+            ListType: nbt.IDTagEnd,
+        },
+    })
+
+    return pocketNBTFile(root, []byte{'E', 'N', 'T', 0, 1, 0, 0, 0})
+}
+
+// Pocket Edition NBT files are little endian and start with a header followed by the NBT length
+func pocketNBTFile(root *nbt.Compound, header []byte) ([]byte, error) {
+    stream := nbt.NewStream(nbt.LittleEndian)
+
+    err := stream.WriteTag(root)
+    if err != nil {
+        return nil, err
+    }
+
+    data := append([]byte{}, header...)
+    data = binary.LittleEndian.AppendUint32(data, uint32(len(stream.Bytes())))
+    return append(data, stream.Bytes()...), nil
+}
+
+func (pocket_level *PocketLevel) chunksBytes() []byte {
+    chunks := pocketWorldSize / 16
+    data := make([]byte, pocketSectorSize + chunks * chunks * pocketChunkSectors * pocketSectorSize)
+
+    blocks := pocket_level.Blocks
+
+    for chunkZ := 0; chunkZ < chunks; chunkZ++ {
+        for chunkX := 0; chunkX < chunks; chunkX++ {
+            // The location table is 32x32 with each entry being the sector offset and the sector count
+            sector := 1 + (chunkZ * chunks + chunkX) * pocketChunkSectors
+            binary.LittleEndian.PutUint32(data[(chunkX + chunkZ * 32) * 4:], uint32(sector << 8 | pocketChunkSectors))
+
+            pocket_level.writeChunk(data[sector * pocketSectorSize:], blocks, chunkX, chunkZ)
+        }
+    }
+
+    return data
+}
+
+// Chunks store blocks, data, sky light and block light as [x][z][y] columns, the last three as nibbles
+func (pocket_level *PocketLevel) writeChunk(data []byte, blocks []int8, chunkX int, chunkZ int) {
+    const columnBlocks = pocketWorldHeight
+    const columnNibbles = pocketWorldHeight / 2
+
+    // Chunk length (82180 bytes) with the flag every legacy chunk has in its high byte
+    binary.LittleEndian.PutUint32(data, 0x20014104)
+
+    blocksStart := 4
+    dataStart := blocksStart + 16 * 16 * columnBlocks
+    skyLightStart := dataStart + 16 * 16 * columnNibbles
+    blockLightStart := skyLightStart + 16 * 16 * columnNibbles
+
+    width := int(pocket_level.Width)
+    length := int(pocket_level.Length)
+    height := int(pocket_level.Height)
+
+    for x := 0; x < 16; x++ {
+        for z := 0; z < 16; z++ {
+            column := x * 16 + z
+            worldX := chunkX * 16 + x
+            worldZ := chunkZ * 16 + z
+
+            skyLight := byte(15)
+            for y := pocketWorldHeight - 1; y >= 0; y-- {
+                var block int8
+                if worldX < width && worldZ < length && y < height {
+                    block = blocks[(y * length + worldZ) * width + worldX]
+                }
+
+                var blockData byte
+                if wool, ok := classicCloth2PocketWool[block]; ok {
+                    block = 35
+                    blockData = byte(wool)
+                }
+                if !isTransparent(block) {
+                    skyLight = 0
+                }
+
+                data[blocksStart + column * columnBlocks + y] = byte(block)
+                setNibble(data[dataStart + column * columnNibbles:], y, blockData)
+                setNibble(data[skyLightStart + column * columnNibbles:], y, skyLight)
+                setNibble(data[blockLightStart + column * columnNibbles:], y, 0)
+            }
+        }
+    }
+}
+
+// Even indices use the low nibble and odd indices the high nibble
+func setNibble(data []byte, index int, value byte) {
+    if index % 2 == 0 {
+        data[index / 2] = (data[index / 2] & 0xf0) | (value & 0x0f)
+    } else {
+        data[index / 2] = (data[index / 2] & 0x0f) | ((value & 0x0f) << 4)
+    }
+}
+
+// Blocks sky light passes straight through
+func isTransparent(block int8) bool {
+    switch block {
+        case 0, 6, 18, 20, 37, 38, 39, 40:
+            return true
+        default:
+            return false
+    }
+}
+
+func ClassicEntity2PocketCompound(entity mc_classic_parser.ClassicEntity) nbt.Compound {
+    entityIds := map[string]int32{
+        "Pig": 12,
+        "Sheep": 13,
+        "Zombie": 32,
+        "Creeper": 33,
+        "Skeleton": 34,
+        "Spider": 35,
+    }
+    id := textureName2Id(entity.TextureName)
+
+    output := nbt.Compound{
+        Value: map[string]nbt.Tag{
+            "id": &nbt.Int{
+                Value: entityIds[id],
+            },
+            "Pos": &nbt.List{
+                Value: []nbt.Tag{
+                    &nbt.Float{Value: entity.X},
+                    &nbt.Float{Value: entity.Y},
+                    &nbt.Float{Value: entity.Z},
+                },
+                ListType: nbt.IDTagFloat,
+            },
+            "Rotation": &nbt.List{
+                Value: []nbt.Tag{
+                    &nbt.Float{Value: entity.YRot},
+                    &nbt.Float{Value: entity.XRot},
+                },
+                ListType: nbt.IDTagFloat,
+            },
+            "Motion": &nbt.List{
+                Value: []nbt.Tag{
+                    &nbt.Float{Value: entity.Xd},
+                    &nbt.Float{Value: entity.Yd},
+                    &nbt.Float{Value: entity.Zd},
+                },
+                ListType: nbt.IDTagFloat,
+            },
+            "FallDistance": &nbt.Float{
+                Value: entity.FallDistance,
+            },
+            "Fire": &nbt.Short{
+                Value: 0,
+            },
+            "Air": &nbt.Short{
+                Value: int16(entity.AirSupply),
+            },
+            "OnGround": &nbt.Byte{
+                Value: ternary[int8](entity.OnGround, 1, 0),
+            },
+            "Health": &nbt.Short{
+                Value: int16(entity.Health),
+            },
+        },
+    }
+    if id == "Sheep" {
+        output.Value["Sheared"] = &nbt.Byte{
+            Value: ternary[int8](entity.HasHair, 0, 1),
+        }
+        output.Value["Color"] = &nbt.Byte{
+            Value: 0,
+        }
+    }
+
+    return output
+}
@@ -0,0 +1,120 @@
+package classic_converter
+
+import (
+    "context"
+    "io"
+    "os"
+)
+
+// Progress is told how far along a conversion is. processed and total are bytes while reading and writing
+// and blocks while converting and building, total is 0 when it isn't known.
+type Progress interface {
+    Report(phase string, processed int64, total int64)
+}
+
+// ProgressFunc lets a plain function be used as a Progress
+type ProgressFunc func(phase string, processed int64, total int64)
+
+func (progress_func ProgressFunc) Report(phase string, processed int64, total int64) {
+    progress_func(phase, processed, total)
+}
+
+func reportProgress(progress Progress, phase string, processed int64, total int64) {
+    if progress != nil {
+        progress.Report(phase, processed, total)
+    }
+}
+
+// blockProgress is embedded in writers that take a while to build their output.
+// It reports how many blocks are done and stops building when the report returns an error.
+type blockProgress struct {
+    report func(processed int64, total int64) error
+}
+
+// SetProgress sets what is called as blocks get built, building stops with the error it returns
+func (block_progress *blockProgress) SetProgress(report func(processed int64, total int64) error) {
+    block_progress.report = report
+}
+
+func (block_progress *blockProgress) step(processed int64, total int64) error {
+    if block_progress.report == nil {
+        return nil
+    }
+    return block_progress.report(processed, total)
+}
+
+// TrackProgress reports how far the writer is with building its output as the "Building" phase and stops it once ctx is cancelled.
+// Writers that build their output quickly don't report anything.
+func TrackProgress(ctx context.Context, writer Writer, progress Progress) {
+    tracked, ok := writer.(interface {
+        SetProgress(report func(processed int64, total int64) error)
+    })
+    if !ok {
+        return
+    }
+    tracked.SetProgress(func(processed int64, total int64) error {
+        reportProgress(progress, "Building", processed, total)
+        return ctx.Err()
+    })
+}
+
+// progressReader reports the bytes read so far and stops reading once ctx is cancelled
+type progressReader struct {
+    ctx context.Context
+    reader io.Reader
+    progress Progress
+    processed int64
+    total int64
+}
+
+func (progress_reader *progressReader) Read(p []byte) (int, error) {
+    if err := progress_reader.ctx.Err(); err != nil {
+        return 0, err
+    }
+    n, err := progress_reader.reader.Read(p)
+    progress_reader.processed += int64(n)
+    if n > 0 {
+        reportProgress(progress_reader.progress, "Reading", progress_reader.processed, progress_reader.total)
+    }
+    return n, err
+}
+
+// progressWriter writes in pieces so it can report the bytes written so far and stop once ctx is cancelled
+type progressWriter struct {
+    ctx context.Context
+    writer io.Writer
+    progress Progress
+    processed int64
+}
+
+func (progress_writer *progressWriter) Write(p []byte) (int, error) {
+    written := 0
+    for written < len(p) {
+        if err := progress_writer.ctx.Err(); err != nil {
+            return written, err
+        }
+        n, err := progress_writer.writer.Write(p[written:clamp(written + 64 * 1024, 0, len(p))])
+        written += n
+        progress_writer.processed += int64(n)
+        reportProgress(progress_writer.progress, "Writing", progress_writer.processed, 0)
+        if err != nil {
+            return written, err
+        }
+    }
+    return written, nil
+}
+
+// Returns how many bytes the reader has left when that can be known, 0 otherwise
+func readerSize(reader io.Reader) int64 {
+    switch sized := reader.(type) {
+    case interface{ Len() int }:
+        return int64(sized.Len())
+    case *os.File:
+        info, err := sized.Stat()
+        if err != nil || !info.Mode().IsRegular() {
+            return 0
+        }
+        return info.Size()
+    }
+    return 0
+}
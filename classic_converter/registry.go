@@ -0,0 +1,295 @@
+package classic_converter
+
+import (
+    "context"
+    "io"
+    "os"
+    "path/filepath"
+    "strings"
+)
+
+// Reader reads a level in its format. Readers don't know the file's name, so formats without one leave the level's name empty.
+type Reader interface {
+    ReadLevel(reader io.Reader) (*Level, error)
+}
+
+// ReaderFunc lets a plain function be used as a Reader
+type ReaderFunc func(reader io.Reader) (*Level, error)
+
+func (reader_func ReaderFunc) ReadLevel(reader io.Reader) (*Level, error) {
+    return reader_func(reader)
+}
+
+// Writer is filled in from a level and then written out, every format's type implements it.
+// WriteTo never touches the filesystem, WriteToFile writes the file and reports what it wrote.
+// Formats written as a folder or as more than one file are written by WriteTo as a zip archive of the files.
+type Writer interface {
+    FromLevel(level *Level)
+    io.WriterTo
+    WriteToFile(filename string) error
+}
+
+// Capabilities says how much of a level a format can hold
+type Capabilities struct {
+    Entities bool
+    Player bool
+    DataValues bool
+    Metadata bool // Can embed metadata with everything else about the level
+    MaxDimensions [3]int16 // Width, length and height, 0 when there is no limit
+    MaxBlock int8 // Highest block the writer has a counterpart for, 0 when it writes every ID as it is
+}
+
+// Options are the settings some writers take, unused ones are ignored
+type Options struct {
+    Format string // Format Convert writes
+    InputFormat string // Format Convert reads, empty detects it from the content
+    TargetVersion string // Classic version blocks are downgraded for, empty uses the format's default
+    Greedy bool
+    Atlas string // Path to classic's terrain.png
+    AtlasData []byte // Contents of classic's terrain.png, used instead of reading Atlas when set
+    Region string // "x1,y1,z1,x2,y2,z2", empty selects the whole world
+    Hollow int
+    BasePlate int
+    BlockSize float64
+    View string // "top", "isometric" or "slices"
+    Angle int
+    Cutaway int
+    Scale int
+    Animated bool
+    IncludeBlocks bool
+    NDJSON bool
+    Progress Progress // Told how far Convert is, nil when nobody is interested
+    Metadata string // MetadataNone, MetadataSidecar or MetadataEmbed
+    BlockTable string // Block table to write with instead of the format's, see BlockTables
+    BlockOverrides *BlockOverrides // Blocks swapped for others before writing, nil when there are none
+    StrictBlocks bool // Blocks the block table has nothing for are an error instead of stone
+    SkyColor string // "RRGGBB" or "default" for the client's own, empty keeps the level's
+    FogColor string
+    CloudColor string
+    WaterLevel int // -1 keeps the level's
+    Spawn string // SpawnKeep, SpawnSafe, SpawnCenter or a block position written as "x,y,z"
+}
+
+func (options *Options) InitWithDefaults() *Options {
+    options.Format = ""
+    options.InputFormat = ""
+    options.TargetVersion = ""
+    options.Greedy = false
+    options.Atlas = ""
+    options.AtlasData = nil
+    options.Region = ""
+    options.Hollow = 0
+    options.BasePlate = 0
+    options.BlockSize = 1
+    options.View = "top"
+    options.Angle = 0
+    options.Cutaway = -1
+    options.Scale = 4
+    options.Animated = false
+    options.IncludeBlocks = false
+    options.NDJSON = false
+    options.Progress = nil
+    options.Metadata = MetadataNone
+    options.BlockTable = ""
+    options.BlockOverrides = nil
+    options.StrictBlocks = false
+    options.SkyColor = ""
+    options.FogColor = ""
+    options.CloudColor = ""
+    options.WaterLevel = -1
+    options.Spawn = SpawnKeep
+
+    return options
+}
+
+// Format is an entry in the format registry
+type Format struct {
+    Name string
+    Description string
+    Extensions []string // Extensions files of the format have, used to pick a reader
+    Sniff Sniffer // nil when the format can't be recognized by its content
+    Capabilities Capabilities
+    BlockTable string // Block table the format is written with, empty is "cpe" which has every block
+    Reader Reader // nil when the format can't be read
+    // Makes a writer set up with the given options and returns the suffix output files get. nil when the format can't be written
+    NewWriter func(options Options) (Writer, string, error)
+}
+
+var registeredFormats = []Format{}
+
+// Adds a format to the registry, formats registered later with the same name replace earlier ones
+func RegisterFormat(format Format) {
+    for i, registered := range registeredFormats {
+        if registered.Name == format.Name {
+            registeredFormats[i] = format
+            return
+        }
+    }
+    registeredFormats = append(registeredFormats, format)
+}
+
+// Returns every registered format in the order they were registered
+func Formats() []Format {
+    return append([]Format{}, registeredFormats...)
+}
+
+func FormatByName(name string) (Format, bool) {
+    for _, format := range registeredFormats {
+        if format.Name == name {
+            return format, true
+        }
+    }
+    return Format{}, false
+}
+
+// Returns the names of every format that can be written
+func WritableFormats() []string {
+    names := []string{}
+    for _, format := range registeredFormats {
+        if format.NewWriter != nil {
+            names = append(names, format.Name)
+        }
+    }
+    return names
+}
+
+// Returns the extensions of every format that can be read
+func ReadableExtensions() []string {
+    extensions := []string{}
+    found := map[string]bool{}
+    for _, format := range registeredFormats {
+        if format.Reader == nil {
+            continue
+        }
+        for _, extension := range format.Extensions {
+            if !found[extension] {
+                found[extension] = true
+                extensions = append(extensions, extension)
+            }
+        }
+    }
+    return extensions
+}
+
+// ReadLevel reads the file with the reader of the format it looks most like, files that can't be recognized are read by their extension.
+func ReadLevel(filename string) (*Level, error) {
+    format, _, err := DetectFile(filename)
+    if err != nil {
+        return nil, err
+    }
+    return format.ReadFile(filename)
+}
+
+// ReadFile reads the file with the format's reader, levels without a name are named after the file
+func (format Format) ReadFile(filename string) (*Level, error) {
+    return format.ReadFileWithProgress(context.Background(), filename, nil)
+}
+
+// ReadFileWithProgress is ReadFile reporting the bytes read as the "Reading" phase, it stops once ctx is cancelled
+func (format Format) ReadFileWithProgress(ctx context.Context, filename string, progress Progress) (*Level, error) {
+    file, err := os.Open(filename)
+    if err != nil {
+        return nil, err
+    }
+    defer file.Close()
+
+    level, err := format.Reader.ReadLevel(&progressReader{ctx: ctx, reader: file, progress: progress, total: readerSize(file)})
+    if err != nil {
+        return nil, readError(format.Name, err)
+    }
+    // A sidecar says more about the level than the file itself
+    metadata, err := readMetadataSidecar(filename)
+    if err != nil {
+        return nil, err
+    }
+    if metadata != nil && level.ApplyMetadata(metadata) {
+        logInfo("Restored the metadata of the %s it was converted from using %s", metadata.SourceFormat, MetadataFilename(filename))
+    }
+
+    if level.Name == "" {
+        level.Name = strings.TrimSuffix(filepath.Base(filename), filepath.Ext(filename))
+    }
+    level.logHeader()
+    return level, nil
+}
+
+// CheckOptions checks the options that apply to every format, the format's NewWriter checks the rest
+func (format Format) CheckOptions(options Options) error {
+    table, ok := BlockTableByName(format.blockTableName(options))
+    if !ok {
+        return newError(ErrInvalidOption, format.Name, nil, "error: Unknown block table %s. Expected one of %s", format.blockTableName(options), strings.Join(BlockTables(), ", "))
+    }
+    if maxBlock := format.Capabilities.MaxBlock; maxBlock > 0 && table.highestBlock() > maxBlock {
+        return newError(ErrInvalidOption, format.Name, nil, "error: The %s block table has blocks up to ID %d, a %s only holds blocks up to ID %d.", table.Name, table.highestBlock(), format.Description, maxBlock)
+    }
+    err := checkEnvironment(options)
+    if err != nil {
+        return err
+    }
+
+    switch options.Metadata {
+    case MetadataNone, MetadataSidecar:
+        return nil
+    case MetadataEmbed:
+        if !format.Capabilities.Metadata {
+            return newError(ErrInvalidOption, format.Name, nil, "error: Metadata can't be embedded in a %s, write it to a sidecar instead.", format.Description)
+        }
+        return nil
+    }
+    return newError(ErrInvalidOption, format.Name, nil, "error: Unknown metadata option %s. Expected one of sidecar, embed", options.Metadata)
+}
+
+// LogSkipped logs every entity and the player when the format can't hold them, only shown when logging verbosely
+func (format Format) LogSkipped(level *Level) {
+    if !format.Capabilities.Entities {
+        for _, entity := range level.Mobs() {
+            logDebug("Skipped entity", map[string]any{"format": format.Name, "type": textureName2Id(entity.TextureName), "position": [3]float32{entity.X, entity.Y, entity.Z}})
+        }
+    }
+    if level.Player != nil && !format.Capabilities.Player {
+        logDebug("Skipped player", map[string]any{"format": format.Name, "position": [3]float32{level.Player.X, level.Player.Y, level.Player.Z}})
+    }
+}
+
+// DetectFile picks the most likely readable format for the file, by content first and by extension when that doesn't work out
+func DetectFile(filename string) (Format, Detection, error) {
+    file, err := os.Open(filename)
+    if err != nil {
+        return Format{}, Detection{}, err
+    }
+    defer file.Close()
+
+    detections, err := Detect(file)
+    if err != nil {
+        return Format{}, Detection{}, err
+    }
+    if format, detection, ok := readableDetection(detections); ok {
+        return format, detection, nil
+    }
+
+    extension := strings.ToLower(filepath.Ext(filename))
+    for _, format := range registeredFormats {
+        if format.Reader == nil {
+            continue
+        }
+        for _, formatExtension := range format.Extensions {
+            if formatExtension == extension {
+                return format, Detection{format.Name, format.Description, 0}, nil
+            }
+        }
+    }
+    if len(detections) > 0 {
+        return Format{}, Detection{}, newError(ErrUnknownFormat, detections[0].Format, nil, "error: %s looks like a %s, which can't be converted from.", filename, detections[0].Kind)
+    }
+    return Format{}, Detection{}, newError(ErrUnknownFormat, "", nil, "error: Could not tell what format %s is.", filename)
+}
+
+// Returns the most likely detection that has a reader
+func readableDetection(detections []Detection) (Format, Detection, bool) {
+    for _, detection := range detections {
+        if format, ok := FormatByName(detection.Format); ok && format.Reader != nil {
+            return format, detection, true
+        }
+    }
+    return Format{}, Detection{}, false
+}
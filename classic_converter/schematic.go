@@ -1,9 +1,7 @@
 package classic_converter
 
 import (
-    "fmt"
-    "io/ioutil"
-    "os"
+    "io"
 
     "github.com/BJTMastermind/go-nbt"
 )
@@ -16,6 +14,8 @@ type Schematic struct {
     Data []int8
     Entities []nbt.Compound
     TileEntities []nbt.Compound
+    EmbedMetadata bool // Keeps everything else about the level in a ClassicConverter compound
+    metadata *LevelMetadata
 }
 
 func (schematic *Schematic) InitWithDefaults() *Schematic {
@@ -26,11 +26,31 @@ func (schematic *Schematic) InitWithDefaults() *Schematic {
     schematic.Data = make([]int8, 256*256*64)
     schematic.Entities = []nbt.Compound{}
     schematic.TileEntities = []nbt.Compound{}
+    schematic.EmbedMetadata = false
 
     return schematic
 }
 
-func (schematic *Schematic) WriteToFile(filename string) {
+// Schematics have no player, only mobs are kept
+func (schematic *Schematic) FromLevel(level *Level) {
+    schematic.Width = level.Width
+    schematic.Length = level.Length
+    schematic.Height = level.Height
+    schematic.Blocks = level.Blocks
+    schematic.Data = level.DataOrZero()
+
+    compoundEntities := []nbt.Compound{}
+    for _, entity := range level.Mobs() {
+        compoundEntities = append(compoundEntities, ClassicEntity2Compound(entity, true))
+    }
+    schematic.Entities = compoundEntities
+
+    if schematic.EmbedMetadata {
+        schematic.metadata = level.Metadata()
+    }
+}
+
+func (schematic *Schematic) WriteTo(w io.Writer) (int64, error) {
     tag := nbt.NewCompoundTag("Schematic", map[string]nbt.Tag{
         "Width": &nbt.Short{
             Value: schematic.Width,
@@ -59,19 +79,30 @@ func (schematic *Schematic) WriteToFile(filename string) {
         },
     })
 
+    if schematic.metadata != nil {
+        compound, err := metadataCompound(schematic.metadata)
+        if err != nil {
+            return 0, err
+        }
+        tag.Value["ClassicConverter"] = compound
+    }
+
     stream := nbt.NewStream(nbt.BigEndian)
 
     err := stream.WriteTag(tag)
     if err != nil {
-        panic(err)
+        return 0, err
     }
 
     data, err := nbt.Compress(stream, nbt.CompressGZip, nbt.DefaultCompressionLevel)
     if err != nil {
-        panic(err)
+        return 0, err
     }
 
-    ioutil.WriteFile(filename, data, os.ModePerm)
+    n, err := w.Write(data)
+    return int64(n), err
+}
 
-    fmt.Printf("Generated %s\n", filename)
+func (schematic *Schematic) WriteToFile(filename string) error {
+    return writeFile(filename, schematic)
 }
@@ -0,0 +1,241 @@
+package classic_converter
+
+import (
+    "bytes"
+    "encoding/binary"
+    "io"
+)
+
+// STLModel is a watertight binary STL mesh of a region of the world, for 3D printing builds.
+// Air and liquids are empty, every other block is solid. The model is Z up like most slicers expect.
+type STLModel struct {
+    Width int16
+    Length int16
+    Height int16
+    Blocks []int8
+    RegionStart [3]int16 // Inclusive x, y and z of the first corner
+    RegionEnd [3]int16 // Inclusive x, y and z of the opposite corner
+    HollowThickness int // Walls this many blocks thick are kept, 0 keeps the model solid
+    BasePlate int // Blocks of solid plate added under the region
+    BlockSize float32 // Size of a block in millimeters
+    blockProgress
+}
+
+func (stl_model *STLModel) InitWithDefaults() *STLModel {
+    stl_model.Width = 256
+    stl_model.Length = 256
+    stl_model.Height = 64
+    stl_model.Blocks = make([]int8, 256*256*64)
+    stl_model.RegionStart = [3]int16{0, 0, 0}
+    stl_model.RegionEnd = [3]int16{255, 63, 255}
+    stl_model.HollowThickness = 0
+    stl_model.BasePlate = 0
+    stl_model.BlockSize = 1
+
+    return stl_model
+}
+
+// Selects the whole world as the region, pick a smaller one after this
+func (stl_model *STLModel) FromLevel(level *Level) {
+    stl_model.Width = level.Width
+    stl_model.Length = level.Length
+    stl_model.Height = level.Height
+    stl_model.Blocks = level.Blocks
+    stl_model.SelectAll()
+}
+
+// Selects the whole world as the region
+func (stl_model *STLModel) SelectAll() {
+    stl_model.RegionStart = [3]int16{0, 0, 0}
+    stl_model.RegionEnd = [3]int16{stl_model.Width - 1, stl_model.Height - 1, stl_model.Length - 1}
+}
+
+func (stl_model *STLModel) WriteTo(w io.Writer) (int64, error) {
+    data, _, _, err := stl_model.stlBytes()
+    if err != nil {
+        return 0, err
+    }
+    n, err := w.Write(data)
+    return int64(n), err
+}
+
+func (stl_model *STLModel) WriteToFile(filename string) error {
+    data, triangleCount, size, err := stl_model.stlBytes()
+    if err != nil {
+        return fileError(filename, err)
+    }
+
+    err = saveFile(filename, data)
+    if err != nil {
+        return err
+    }
+
+    logInfo("Generated %s (%d triangles, %dx%dx%d blocks)", filename, triangleCount, size[0], size[2], size[1])
+    return nil
+}
+
+// Returns the whole file, how many triangles it has and the size of the printed region with the base plate
+func (stl_model *STLModel) stlBytes() ([]byte, int, [3]int, error) {
+    width := int(stl_model.Width)
+    length := int(stl_model.Length)
+    height := int(stl_model.Height)
+
+    // Corners can be given in any order and are kept inside the world
+    var start, size [3]int
+    for axis, limit := range [3]int{width, height, length} {
+        first := clamp(int(stl_model.RegionStart[axis]), 0, limit - 1)
+        second := clamp(int(stl_model.RegionEnd[axis]), 0, limit - 1)
+        start[axis] = ternary(first < second, first, second)
+        size[axis] = ternary(first < second, second - first, first - second) + 1
+    }
+
+    solid := make([]bool, size[0] * size[1] * size[2])
+    index := func(x int, y int, z int) int {
+        return (y * size[2] + z) * size[0] + x
+    }
+    for y := 0; y < size[1]; y++ {
+        for z := 0; z < size[2]; z++ {
+            for x := 0; x < size[0]; x++ {
+                block := stl_model.Blocks[((start[1] + y) * length + start[2] + z) * width + start[0] + x]
+                solid[index(x, y, z)] = block != 0 && (block < 8 || block > 11)
+            }
+        }
+    }
+
+    if stl_model.HollowThickness > 0 {
+        solid = hollowSolid(solid, size, stl_model.HollowThickness)
+    }
+
+    // The base plate goes under everything, so the region moves up to make room for it
+    if stl_model.BasePlate > 0 {
+        plate := make([]bool, size[0] * stl_model.BasePlate * size[2])
+        for i := range plate {
+            plate[i] = true
+        }
+        solid = append(plate, solid...)
+        size[1] += stl_model.BasePlate
+    }
+
+    isSolid := func(position [3]int) bool {
+        for axis := 0; axis < 3; axis++ {
+            if position[axis] < 0 || position[axis] >= size[axis] {
+                return false
+            }
+        }
+        return solid[index(position[0], position[1], position[2])]
+    }
+
+    triangles := new(bytes.Buffer)
+    triangleCount := 0
+    for y := 0; y < size[1]; y++ {
+        for z := 0; z < size[2]; z++ {
+            for x := 0; x < size[0]; x++ {
+                if !isSolid([3]int{x, y, z}) {
+                    continue
+                }
+
+                min := [3]float32{float32(x), float32(y), float32(z)}
+                max := [3]float32{float32(x + 1), float32(y + 1), float32(z + 1)}
+                for axis := 0; axis < 3; axis++ {
+                    for _, sign := range []int{-1, 1} {
+                        neighbor := [3]int{x, y, z}
+                        neighbor[axis] += sign
+                        if isSolid(neighbor) {
+                            continue
+                        }
+
+                        face := boxFace(1, axis, sign, min, max)
+                        for _, triangle := range [2][3]int{{0, 1, 2}, {0, 2, 3}} {
+                            binary.Write(triangles, binary.LittleEndian, stlNormal(face.Normal))
+                            for _, corner := range triangle {
+                                binary.Write(triangles, binary.LittleEndian, stlPosition(face.Positions[corner], size[2], stl_model.BlockSize))
+                            }
+                            binary.Write(triangles, binary.LittleEndian, uint16(0))
+                            triangleCount++
+                        }
+                    }
+                }
+            }
+        }
+
+        if err := stl_model.step(int64((y + 1) * size[0] * size[2]), int64(size[0] * size[1] * size[2])); err != nil {
+            return nil, 0, size, err
+        }
+    }
+
+    // Header is 80 bytes that must not start with "solid", followed by the triangle count
+    header := make([]byte, 80)
+    copy(header, "Classic-Converter STL")
+
+    buffer := new(bytes.Buffer)
+    buffer.Write(header)
+    binary.Write(buffer, binary.LittleEndian, uint32(triangleCount))
+    buffer.Write(triangles.Bytes())
+
+    return buffer.Bytes(), triangleCount, size, nil
+}
+
+// STL is z up, so z is flipped into y and y becomes z, which keeps the mesh from getting mirrored
+func stlNormal(normal [3]float32) [3]float32 {
+    return [3]float32{normal[0], -normal[2], normal[1]}
+}
+
+func stlPosition(position [3]float32, length int, scale float32) [3]float32 {
+    return [3]float32{position[0] * scale, (float32(length) - position[2]) * scale, position[1] * scale}
+}
+
+// Empties every solid block more than thickness blocks away from an empty block or the edge of the region
+func hollowSolid(solid []bool, size [3]int, thickness int) []bool {
+    index := func(position [3]int) int {
+        return (position[1] * size[2] + position[2]) * size[0] + position[0]
+    }
+
+    // Breadth first search outwards from every empty block, blocks on the edge touch the empty outside
+    distance := make([]int, len(solid))
+    queue := [][3]int{}
+    edges := [][3]int{}
+    for y := 0; y < size[1]; y++ {
+        for z := 0; z < size[2]; z++ {
+            for x := 0; x < size[0]; x++ {
+                position := [3]int{x, y, z}
+                onEdge := x == 0 || y == 0 || z == 0 || x == size[0] - 1 || y == size[1] - 1 || z == size[2] - 1
+                switch {
+                case !solid[index(position)]:
+                    distance[index(position)] = 0
+                case onEdge:
+                    distance[index(position)] = 1
+                    edges = append(edges, position)
+                    continue
+                default:
+                    distance[index(position)] = -1
+                    continue
+                }
+                queue = append(queue, position)
+            }
+        }
+    }
+
+    // Edge blocks go after the empty ones so the queue stays ordered by distance
+    queue = append(queue, edges...)
+    for len(queue) > 0 {
+        position := queue[0]
+        queue = queue[1:]
+        for axis := 0; axis < 3; axis++ {
+            for _, sign := range []int{-1, 1} {
+                neighbor := position
+                neighbor[axis] += sign
+                if neighbor[axis] < 0 || neighbor[axis] >= size[axis] || distance[index(neighbor)] != -1 {
+                    continue
+                }
+                distance[index(neighbor)] = distance[index(position)] + 1
+                queue = append(queue, neighbor)
+            }
+        }
+    }
+
+    hollowed := make([]bool, len(solid))
+    for i := range solid {
+        hollowed[i] = solid[i] && distance[i] <= thickness
+    }
+    return hollowed
+}
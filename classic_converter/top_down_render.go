@@ -0,0 +1,113 @@
+package classic_converter
+
+import (
+    "bytes"
+    "image"
+    "image/color"
+    "image/png"
+    "io"
+)
+
+// TopDownRender is a map of the world seen from above, one pixel per block column.
+// See-through blocks like water and glass are blended over whatever is under them and higher blocks are brighter.
+type TopDownRender struct {
+    Width int16
+    Length int16
+    Height int16
+    Blocks []int8
+}
+
+func (top_down_render *TopDownRender) InitWithDefaults() *TopDownRender {
+    top_down_render.Width = 256
+    top_down_render.Length = 256
+    top_down_render.Height = 64
+    top_down_render.Blocks = make([]int8, 256*256*64)
+
+    return top_down_render
+}
+
+func (top_down_render *TopDownRender) FromLevel(level *Level) {
+    top_down_render.Width = level.Width
+    top_down_render.Length = level.Length
+    top_down_render.Height = level.Height
+    top_down_render.Blocks = level.Blocks
+}
+
+func (top_down_render *TopDownRender) Render() *image.RGBA {
+    width := int32(top_down_render.Width)
+    length := int32(top_down_render.Length)
+    height := int32(top_down_render.Height)
+
+    img := image.NewRGBA(image.Rect(0, 0, int(width), int(length)))
+    heights := make([]int32, width * length)
+
+    for z := int32(0); z < length; z++ {
+        for x := int32(0); x < width; x++ {
+            // Same as getHightestTile, but water and lava count as the top
+            top := int32(-1)
+            for y := height - 1; y >= 0; y-- {
+                if top_down_render.Blocks[(y * length + z) * width + x] != 0 {
+                    top = y
+                    break
+                }
+            }
+            heights[z * width + x] = top
+            if top < 0 {
+                continue
+            }
+
+            // Blend down through see-through blocks until an opaque one is found
+            var red, green, blue, coverage float32
+            for y := top; y >= 0 && coverage < 0.99; y-- {
+                block := top_down_render.Blocks[(y * length + z) * width + x]
+                if block == 0 {
+                    continue
+                }
+
+                blockColor := ClassicBlockColor(block)
+                alpha := float32(blockColor.A) / 255 * (1 - coverage)
+                red += float32(blockColor.R) * alpha
+                green += float32(blockColor.G) * alpha
+                blue += float32(blockColor.B) * alpha
+                coverage += alpha
+            }
+
+            // Low columns are darker, blocks higher than the block to their north get lit up like on a map
+            shade := 0.6 + 0.4 * float32(top) / float32(clamp(height - 1, 1, height))
+            if z > 0 {
+                north := heights[(z - 1) * width + x]
+                switch {
+                case top > north:
+                    shade *= 1.1
+                case top < north:
+                    shade *= 0.85
+                }
+            }
+
+            img.SetRGBA(int(x), int(z), color.RGBA{
+                R: uint8(clamp(red / coverage * shade, 0, 255)),
+                G: uint8(clamp(green / coverage * shade, 0, 255)),
+                B: uint8(clamp(blue / coverage * shade, 0, 255)),
+                A: 255,
+            })
+        }
+    }
+
+    return img
+}
+
+func (top_down_render *TopDownRender) WriteTo(w io.Writer) (int64, error) {
+    buffer := new(bytes.Buffer)
+
+    err := png.Encode(buffer, top_down_render.Render())
+    if err != nil {
+        return 0, err
+    }
+
+    n, err := w.Write(buffer.Bytes())
+    return int64(n), err
+}
+
+func (top_down_render *TopDownRender) WriteToFile(filename string) error {
+    return writeFile(filename, top_down_render)
+}
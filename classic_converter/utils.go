@@ -1,6 +1,15 @@
 package classic_converter
 
 import (
+    "archive/zip"
+    "bytes"
+    "crypto/rand"
+    "io"
+    mathrand "math/rand"
+    "os"
+    "path/filepath"
+    "sort"
+
     "github.com/BJTMastermind/Go-MC-Classic-Parser"
     "github.com/BJTMastermind/go-nbt"
 )
@@ -12,6 +21,86 @@ func ternary[T any](condition bool, a T, b T) T {
     return b
 }
 
+func clamp[T int | int16 | int32 | float32 | float64](value T, low T, high T) T {
+    if value < low {
+        return low
+    }
+    if value > high {
+        return high
+    }
+    return value
+}
+
+// Writes everything the writer gives to the file
+func writeFile(filename string, writer io.WriterTo) error {
+    buffer := new(bytes.Buffer)
+
+    _, err := writer.WriteTo(buffer)
+    if err != nil {
+        return fileError(filename, err)
+    }
+
+    err = saveFile(filename, buffer.Bytes())
+    if err != nil {
+        return err
+    }
+
+    logInfo("Generated %s", filename)
+    return nil
+}
+
+// Writes the file readable by everyone but only writable by its owner
+func saveFile(filename string, data []byte) error {
+    err := os.WriteFile(filename, data, 0644)
+    if err != nil {
+        return fileError(filename, err)
+    }
+    return nil
+}
+
+// Writes the files of a folder format into the folder, creating it if needed
+func saveFolder(foldername string, files map[string][]byte) error {
+    err := os.MkdirAll(foldername, 0755)
+    if err != nil {
+        return newError(ErrWriteFailed, "", err, "error: Could not make the folder %s, %s.", foldername, err)
+    }
+
+    for _, name := range sortedKeys(files) {
+        err := saveFile(filepath.Join(foldername, name), files[name])
+        if err != nil {
+            return err
+        }
+    }
+    return nil
+}
+
+// Packs the files of a folder format into a zip archive, in name order so the archive is the same every time
+func writeZip(w io.Writer, files map[string][]byte) (int64, error) {
+    names := make([]string, 0, len(files))
+    for name := range files {
+        names = append(names, name)
+    }
+    sort.Strings(names)
+
+    buffer := new(bytes.Buffer)
+    zipWriter := zip.NewWriter(buffer)
+    for _, name := range names {
+        file, err := zipWriter.Create(name)
+        if err != nil {
+            return 0, err
+        }
+        _, err = file.Write(files[name])
+        if err != nil {
+            return 0, err
+        }
+    }
+    err := zipWriter.Close()
+    if err != nil {
+        return 0, err
+    }
+    return buffer.WriteTo(w)
+}
+
 func compoundArrayToTagArray(compoundArray []nbt.Compound) []nbt.Tag {
     out := make([]nbt.Tag, len(compoundArray))
     for i, compound := range compoundArray {
@@ -21,6 +110,60 @@ func compoundArrayToTagArray(compoundArray []nbt.Compound) []nbt.Tag {
     return out
 }
 
+func findSpawn(blocks []int8, width int16, length int16, height int16) [3]int16 {
+    i := 0
+
+    var x int32
+    var y int32
+    var z int32
+    for ok := true; ok; ok = (y <= int32(height) / 2) { // do while loop
+        i++
+        x = mathrand.Int31n(int32(width) / 2) + int32(width) / 4
+        z = mathrand.Int31n(int32(length) / 2) + int32(length) / 4
+        y = getHightestTile(blocks, width, length, height, x, z) + 1
+        if i == 10000 {
+            return [3]int16{int16(x), -100, int16(z)}
+        }
+    }
+
+    return [3]int16{int16(x), int16(y), int16(z)}
+}
+
+// Same as findSpawn but falls back to the middle of the map instead of giving up with a Y of -100
+func findSafeSpawn(blocks []int8, width int16, length int16, height int16) [3]int16 {
+    spawn := findSpawn(blocks, width, length, height)
+    if spawn[1] < 0 {
+        spawn[0] = width / 2
+        spawn[2] = length / 2
+        spawn[1] = int16(getHightestTile(blocks, width, length, height, int32(spawn[0]), int32(spawn[2])) + 1)
+    }
+    return spawn
+}
+
+func getHightestTile(blocks []int8, width int16, length int16, height int16, x int32, z int32) int32 {
+    for y := int32(height) - 1; y >= 0; y-- {
+        index := (y * int32(length) + z) * int32(width) + x
+        if blocks[index] != 0 && blocks[index] != 8 && blocks[index] != 9 && blocks[index] != 10 && blocks[index] != 11 {
+            return y
+        }
+    }
+    return int32(height) / 2
+}
+
+// Converts a rotation in degrees to the 0-255 range used by classic servers
+func Degrees2Byte(degrees float32) int8 {
+    return int8(uint8(int32(degrees * 256 / 360) & 0xff))
+}
+
+// Generates a random (version 4) UUID
+func newUUID() []int8 {
+    uuid := make([]byte, 16)
+    rand.Read(uuid)
+    uuid[6] = (uuid[6] & 0x0f) | 0x40
+    uuid[8] = (uuid[8] & 0x3f) | 0x80
+    return ByteArray2Int8Array(uuid)
+}
+
 func textureName2Id(textureName string) string {
     switch textureName {
         case "/mob/zombie.png":
@@ -36,6 +179,7 @@ func textureName2Id(textureName string) string {
         case "/mob/sheep.png":
             return "Sheep"
         default:
+            logDebug("Unknown entity texture, treated as a zombie", map[string]any{"texture": textureName})
             return "Zombie"
     }
 }
@@ -0,0 +1,181 @@
+package classic_converter
+
+import (
+    "bytes"
+    "fmt"
+    "io"
+    "path/filepath"
+    "sort"
+    "strings"
+)
+
+// WavefrontOBJ is a Wavefront OBJ mesh of every visible block face with a MTL file next to it.
+// Every block gets its own material and faces are split into opaque, transparent and liquid objects.
+type WavefrontOBJ struct {
+    Width int16
+    Length int16
+    Height int16
+    Blocks []int8
+    Greedy bool
+    TextureAtlas string // Path to classic's terrain.png, as written in the MTL file
+    MaterialLibrary string // Name of the MTL file the OBJ refers to when written with WriteTo
+    blockProgress
+    groups *[3][]meshFace // Built on the first write and shared by WriteTo and WriteMaterialsTo
+}
+
+func (wavefront_obj *WavefrontOBJ) InitWithDefaults() *WavefrontOBJ {
+    wavefront_obj.Width = 256
+    wavefront_obj.Length = 256
+    wavefront_obj.Height = 64
+    wavefront_obj.Blocks = make([]int8, 256*256*64)
+    wavefront_obj.Greedy = false
+    wavefront_obj.TextureAtlas = "terrain.png"
+    wavefront_obj.MaterialLibrary = "world.mtl"
+    wavefront_obj.groups = nil
+
+    return wavefront_obj
+}
+
+func (wavefront_obj *WavefrontOBJ) FromLevel(level *Level) {
+    wavefront_obj.Width = level.Width
+    wavefront_obj.Length = level.Length
+    wavefront_obj.Height = level.Height
+    wavefront_obj.Blocks = level.Blocks
+    wavefront_obj.groups = nil
+}
+
+// Writes the OBJ file, the materials it uses are written with WriteMaterialsTo
+func (wavefront_obj *WavefrontOBJ) WriteTo(w io.Writer) (int64, error) {
+    obj, _, _, err := wavefront_obj.meshBytes(wavefront_obj.MaterialLibrary)
+    if err != nil {
+        return 0, err
+    }
+    n, err := w.Write(obj)
+    return int64(n), err
+}
+
+func (wavefront_obj *WavefrontOBJ) WriteMaterialsTo(w io.Writer) (int64, error) {
+    _, mtl, _, err := wavefront_obj.meshBytes(wavefront_obj.MaterialLibrary)
+    if err != nil {
+        return 0, err
+    }
+    n, err := w.Write(mtl)
+    return int64(n), err
+}
+
+// Writes the OBJ file and a MTL file with the same name
+func (wavefront_obj *WavefrontOBJ) WriteToFile(filename string) error {
+    mtlFilename := strings.TrimSuffix(filename, filepath.Ext(filename)) + ".mtl"
+
+    obj, mtl, faceCount, err := wavefront_obj.meshBytes(filepath.Base(mtlFilename))
+    if err != nil {
+        return fileError(filename, err)
+    }
+
+    err = saveFile(filename, obj)
+    if err != nil {
+        return err
+    }
+    err = saveFile(mtlFilename, mtl)
+    if err != nil {
+        return err
+    }
+
+    logInfo("Generated %s and %s (%d faces)", filename, mtlFilename, faceCount)
+    return nil
+}
+
+// Returns the OBJ file refering to the given MTL file, the MTL file and how many faces there are
+func (wavefront_obj *WavefrontOBJ) meshBytes(mtlFilename string) ([]byte, []byte, int, error) {
+    groups, err := wavefront_obj.mesh()
+    if err != nil {
+        return nil, nil, 0, err
+    }
+
+    obj := new(bytes.Buffer)
+    fmt.Fprintf(obj, "mtllib %s\n", mtlFilename)
+
+    normals := map[[3]float32]int{}
+    usedBlocks := map[int8]bool{}
+    vertexCount := 0
+    faceCount := 0
+    for group, faces := range groups {
+        if len(faces) == 0 {
+            continue
+        }
+
+        // Faces of the same block are kept together so each material is only used once
+        sort.SliceStable(faces, func(i, j int) bool {
+            return faces[i].Block < faces[j].Block
+        })
+
+        fmt.Fprintf(obj, "o %s\n", meshGroupNames[group])
+        for i, face := range faces {
+            if i == 0 || faces[i - 1].Block != face.Block {
+                fmt.Fprintf(obj, "usemtl %s\n", ClassicBlockName(face.Block))
+                usedBlocks[face.Block] = true
+            }
+
+            normal, ok := normals[face.Normal]
+            if !ok {
+                normal = len(normals) + 1
+                normals[face.Normal] = normal
+                fmt.Fprintf(obj, "vn %g %g %g\n", face.Normal[0], face.Normal[1], face.Normal[2])
+            }
+
+            for k := 0; k < 4; k++ {
+                fmt.Fprintf(obj, "v %g %g %g\n", face.Positions[k][0], face.Positions[k][1], face.Positions[k][2])
+                fmt.Fprintf(obj, "vt %g %g\n", face.UVs[k][0], face.UVs[k][1])
+            }
+
+            // OBJ indices start at 1 and positions and UVs are written in pairs, so they share indices
+            obj.WriteString("f")
+            for k := 1; k <= 4; k++ {
+                fmt.Fprintf(obj, " %d/%d/%d", vertexCount + k, vertexCount + k, normal)
+            }
+            obj.WriteString("\n")
+
+            vertexCount += 4
+            faceCount++
+        }
+    }
+
+    blocks := make([]int8, 0, len(usedBlocks))
+    for block := range usedBlocks {
+        blocks = append(blocks, block)
+    }
+    sort.Slice(blocks, func(i, j int) bool {
+        return blocks[i] < blocks[j]
+    })
+
+    mtl := new(bytes.Buffer)
+    for _, block := range blocks {
+        blockColor := ClassicBlockColor(block)
+
+        fmt.Fprintf(mtl, "newmtl %s\n", ClassicBlockName(block))
+        fmt.Fprintf(mtl, "Kd %.3f %.3f %.3f\n", float32(blockColor.R) / 255, float32(blockColor.G) / 255, float32(blockColor.B) / 255)
+        mtl.WriteString("Ka 0 0 0\nKs 0 0 0\nillum 1\n")
+        fmt.Fprintf(mtl, "map_Kd %s\n", wavefront_obj.TextureAtlas)
+        if blockMeshGroup(block) != meshOpaque {
+            fmt.Fprintf(mtl, "d %.3f\n", float32(blockColor.A) / 255)
+            fmt.Fprintf(mtl, "map_d %s\n", wavefront_obj.TextureAtlas)
+        }
+        mtl.WriteString("\n")
+    }
+
+    return obj.Bytes(), mtl.Bytes(), faceCount, nil
+}
+
+// Meshes the world once, so writing the OBJ and then the MTL file doesn't build it twice
+func (wavefront_obj *WavefrontOBJ) mesh() ([3][]meshFace, error) {
+    if wavefront_obj.groups != nil {
+        return *wavefront_obj.groups, nil
+    }
+
+    groups, err := buildBlockMesh(wavefront_obj.Blocks, int(wavefront_obj.Width), int(wavefront_obj.Length), int(wavefront_obj.Height), wavefront_obj.Greedy, wavefront_obj.step)
+    if err != nil {
+        return groups, err
+    }
+    wavefront_obj.groups = &groups
+    return groups, nil
+}
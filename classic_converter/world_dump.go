@@ -0,0 +1,245 @@
+package classic_converter
+
+import (
+    "bytes"
+    "encoding/json"
+    "fmt"
+    "io"
+
+    "github.com/BJTMastermind/Go-MC-Classic-Parser"
+)
+
+// WorldDump is everything known about a world as JSON, for diffing, indexing and scripting over saves.
+// Blocks are only written when IncludeBlocks is set, as runs of [block, count] in the usual y, z, x order.
+// With NDJSON set every part is written on its own line instead, the header first, then every entity, the player and the blocks.
+type WorldDump struct {
+    Name string `json:"name"`
+    Creator string `json:"creator"`
+    CreateTime int64 `json:"createTime"` // Milliseconds since the unix epoch
+    Dimensions DumpDimensions `json:"dimensions"`
+    Spawn *DumpSpawn `json:"spawn,omitempty"` // Only known when the format has it
+    Colors DumpColors `json:"colors"`
+    WaterLevel int32 `json:"waterLevel"`
+    CreativeMode bool `json:"creativeMode"`
+    GrowTrees bool `json:"growTrees"`
+    Entities []DumpEntity `json:"entities,omitempty"`
+    Player *DumpPlayer `json:"player,omitempty"`
+    BlockRuns [][2]int `json:"blocks,omitempty"`
+
+    Blocks []int8 `json:"-"`
+    IncludeBlocks bool `json:"-"`
+    NDJSON bool `json:"-"`
+}
+
+type DumpDimensions struct {
+    Width int16 `json:"width"`
+    Length int16 `json:"length"`
+    Height int16 `json:"height"`
+}
+
+type DumpSpawn struct {
+    X int32 `json:"x"`
+    Y int32 `json:"y"`
+    Z int32 `json:"z"`
+    Rotation float32 `json:"rotation"`
+}
+
+// Colors are written as "#rrggbb", -1 (the client default) is left out
+type DumpColors struct {
+    Sky string `json:"sky,omitempty"`
+    Fog string `json:"fog,omitempty"`
+    Cloud string `json:"cloud,omitempty"`
+}
+
+type DumpEntity struct {
+    Type string `json:"type"`
+    Texture string `json:"texture"`
+    Position [3]float32 `json:"position"`
+    Rotation [2]float32 `json:"rotation"`
+    Motion [3]float32 `json:"motion"`
+    Health int32 `json:"health"`
+    OnGround bool `json:"onGround"`
+}
+
+type DumpPlayer struct {
+    Position [3]float32 `json:"position"`
+    Rotation [2]float32 `json:"rotation"`
+    Health int32 `json:"health"`
+    Score int32 `json:"score"`
+    Arrows int32 `json:"arrows"`
+    Inventory []DumpItem `json:"inventory"`
+    Selected int32 `json:"selected"`
+}
+
+type DumpItem struct {
+    Slot int `json:"slot"`
+    Block int32 `json:"block"`
+    Count int32 `json:"count"`
+}
+
+func (world_dump *WorldDump) InitWithDefaults() *WorldDump {
+    world_dump.Name = "A Nice World"
+    world_dump.Creator = ""
+    world_dump.CreateTime = 0
+    world_dump.Dimensions = DumpDimensions{256, 256, 64}
+    world_dump.Colors = DumpColors{}
+    world_dump.WaterLevel = 32
+    world_dump.CreativeMode = false
+    world_dump.GrowTrees = false
+    world_dump.Blocks = make([]int8, 256*256*64)
+    world_dump.IncludeBlocks = false
+    world_dump.NDJSON = false
+
+    return world_dump
+}
+
+// Spawn is only written when the level knows it, the player when the level has one
+func (world_dump *WorldDump) FromLevel(level *Level) {
+    world_dump.Name = level.Name
+    world_dump.Creator = level.Author
+    world_dump.CreateTime = level.CreatedOn
+    world_dump.Dimensions = DumpDimensions{level.Width, level.Length, level.Height}
+    world_dump.Blocks = level.Blocks
+
+    if level.HasSpawn {
+        world_dump.Spawn = &DumpSpawn{int32(level.Spawn[0]), int32(level.Spawn[1]), int32(level.Spawn[2]), level.SpawnYaw}
+    }
+    world_dump.Colors = DumpColors{
+        Sky: dumpColor(level.SkyColor),
+        Fog: dumpColor(level.FogColor),
+        Cloud: dumpColor(level.CloudColor),
+    }
+    world_dump.WaterLevel = level.WaterLevel
+    world_dump.CreativeMode = level.CreativeMode
+    world_dump.GrowTrees = level.GrowTrees
+
+    world_dump.Entities = []DumpEntity{}
+    for _, entity := range level.Mobs() {
+        world_dump.Entities = append(world_dump.Entities, ClassicEntity2Dump(entity))
+    }
+
+    if level.Player != nil {
+        player := ClassicPlayer2Dump(*level.Player)
+        world_dump.Player = &player
+    }
+}
+
+func (world_dump *WorldDump) WriteTo(w io.Writer) (int64, error) {
+    dump := *world_dump
+    if world_dump.IncludeBlocks {
+        dump.BlockRuns = runLengthBlocks(world_dump.Blocks)
+    }
+
+    buffer := new(bytes.Buffer)
+
+    if !world_dump.NDJSON {
+        encoder := json.NewEncoder(buffer)
+        encoder.SetIndent("", "  ")
+
+        err := encoder.Encode(dump)
+        if err != nil {
+            return 0, err
+        }
+    } else {
+        // Every line says what it is with its "type" field
+        header := dump
+        header.Entities = nil
+        header.Player = nil
+        header.BlockRuns = nil
+
+        lines := []any{struct {
+            Type string `json:"type"`
+            WorldDump
+        }{"level", header}}
+        for _, entity := range dump.Entities {
+            lines = append(lines, struct {
+                Type string `json:"type"`
+                Entity DumpEntity `json:"entity"`
+            }{"entity", entity})
+        }
+        if dump.Player != nil {
+            lines = append(lines, struct {
+                Type string `json:"type"`
+                Player DumpPlayer `json:"player"`
+            }{"player", *dump.Player})
+        }
+        if dump.BlockRuns != nil {
+            lines = append(lines, struct {
+                Type string `json:"type"`
+                Blocks [][2]int `json:"blocks"`
+            }{"blocks", dump.BlockRuns})
+        }
+
+        encoder := json.NewEncoder(buffer)
+        for _, line := range lines {
+            err := encoder.Encode(line)
+            if err != nil {
+                return 0, err
+            }
+        }
+    }
+
+    n, err := w.Write(buffer.Bytes())
+    return int64(n), err
+}
+
+func (world_dump *WorldDump) WriteToFile(filename string) error {
+    return writeFile(filename, world_dump)
+}
+
+func ClassicEntity2Dump(entity mc_classic_parser.ClassicEntity) DumpEntity {
+    return DumpEntity{
+        Type: textureName2Id(entity.TextureName),
+        Texture: entity.TextureName,
+        Position: [3]float32{entity.X, entity.Y, entity.Z},
+        Rotation: [2]float32{entity.YRot, entity.XRot},
+        Motion: [3]float32{entity.Xd, entity.Yd, entity.Zd},
+        Health: entity.Health,
+        OnGround: entity.OnGround,
+    }
+}
+
+// Only the 9 hotbar slots are written, empty slots are skipped
+func ClassicPlayer2Dump(player mc_classic_parser.ClassicPlayer) DumpPlayer {
+    slots, _ := player.Inventory["slots"].([]int32)
+    count, _ := player.Inventory["count"].([]int32)
+    selected, _ := player.Inventory["selected"].(int32)
+
+    inventory := []DumpItem{}
+    for i := 0; i < len(slots) && i < len(count); i++ {
+        if slots[i] == -1 {
+            continue
+        }
+        inventory = append(inventory, DumpItem{i, slots[i], count[i]})
+    }
+
+    return DumpPlayer{
+        Position: [3]float32{player.X, player.Y, player.Z},
+        Rotation: [2]float32{player.YRot, player.XRot},
+        Health: player.Health,
+        Score: player.Score,
+        Arrows: player.Arrows,
+        Inventory: inventory,
+        Selected: selected,
+    }
+}
+
+func dumpColor(color int32) string {
+    if color < 0 {
+        return ""
+    }
+    return fmt.Sprintf("#%06x", color & 0xffffff)
+}
+
+// Packs the blocks into runs of [block, count]
+func runLengthBlocks(blocks []int8) [][2]int {
+    runs := [][2]int{}
+    for i, block := range blocks {
+        if i > 0 && int(block) == runs[len(runs) - 1][0] {
+            runs[len(runs) - 1][1]++
+            continue
+        }
+        runs = append(runs, [2]int{int(block), 1})
+    }
+    return runs
+}
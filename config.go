@@ -0,0 +1,126 @@
+package main
+
+import (
+    "errors"
+    "fmt"
+    "os"
+    "path/filepath"
+    "sort"
+    "strings"
+
+    "github.com/BurntSushi/toml"
+    "github.com/akamensky/argparse"
+)
+
+// setting is a flag that can be set from a config file, value points at the variable the flag was parsed into
+type setting struct {
+    value any
+    choices []string // Values a selector flag takes, nil for any value
+    path bool // Relative paths are relative to the config file instead of the working directory
+}
+
+// defaultConfigFile is the config used when --config isn't given, nothing is loaded when it doesn't exist.
+func defaultConfigFile() string {
+    configDir, err := os.UserConfigDir()
+    if err != nil {
+        return ""
+    }
+    return filepath.Join(configDir, "Classic-Converter", "config.toml")
+}
+
+// loadConfig sets every flag that wasn't on the command line from the config file. Keys are the long flag names,
+// the top level sets defaults and the preset's table under [presets.<name>] replaces them.
+func loadConfig(filename string, preset string, argparser *argparse.Parser, settings map[string]setting) error {
+    config := map[string]any{}
+    _, err := toml.DecodeFile(filename, &config)
+    if err != nil {
+        return fmt.Errorf("error: Could not read the config %s, %s.", filename, err)
+    }
+
+    presets, _ := config["presets"].(map[string]any)
+    delete(config, "presets")
+    if preset != "" {
+        values, ok := presets[preset].(map[string]any)
+        if !ok {
+            return fmt.Errorf("error: The config %s has no preset %s. Expected one of %s", filename, preset, strings.Join(sortedNames(presets), ", "))
+        }
+        for key, value := range values {
+            config[key] = value
+        }
+    }
+
+    for _, key := range sortedNames(config) {
+        setting, ok := settings[key]
+        if !ok {
+            return fmt.Errorf("error: Unknown key %s in the config %s. Expected one of %s", key, filename, strings.Join(sortedNames(settings), ", "))
+        }
+        if argumentGiven(argparser, key) {
+            continue
+        }
+        err := setting.set(config[key], filepath.Dir(filename))
+        if err != nil {
+            return fmt.Errorf("error: %s in the config %s %s.", key, filename, err)
+        }
+    }
+    return nil
+}
+
+// set stores the config value in the flag's variable, TOML integers work for float flags too
+func (setting setting) set(value any, configDir string) error {
+    switch pointer := setting.value.(type) {
+    case *string:
+        text, ok := value.(string)
+        if !ok {
+            return fmt.Errorf("must be a string, got %v", value)
+        }
+        if len(setting.choices) > 0 && !contains(setting.choices, text) {
+            return fmt.Errorf("must be one of %s, got %s", strings.Join(setting.choices, ", "), text)
+        }
+        if setting.path && text != "" && !filepath.IsAbs(text) {
+            text = filepath.Join(configDir, text)
+        }
+        *pointer = text
+    case *bool:
+        flag, ok := value.(bool)
+        if !ok {
+            return fmt.Errorf("must be true or false, got %v", value)
+        }
+        *pointer = flag
+    case *int:
+        number, ok := value.(int64)
+        if !ok {
+            return fmt.Errorf("must be a whole number, got %v", value)
+        }
+        *pointer = int(number)
+    case *float64:
+        switch number := value.(type) {
+        case float64:
+            *pointer = number
+        case int64:
+            *pointer = float64(number)
+        default:
+            return fmt.Errorf("must be a number, got %v", value)
+        }
+    default:
+        return errors.New("can't be set from a config")
+    }
+    return nil
+}
+
+func sortedNames[T any](values map[string]T) []string {
+    names := make([]string, 0, len(values))
+    for name := range values {
+        names = append(names, name)
+    }
+    sort.Strings(names)
+    return names
+}
+
+func contains(values []string, value string) bool {
+    for _, candidate := range values {
+        if candidate == value {
+            return true
+        }
+    }
+    return false
+}
@@ -3,10 +3,15 @@ module github.com/BJTMastermind/Classic-Converter
 go 1.20
 
 require (
-    github.com/BJTMastermind/Go-MC-Classic-Parser v0.2.2 // indirect
-    github.com/BJTMastermind/go-nbt v1.2.4 // indirect
-    github.com/akamensky/argparse v1.4.0 // indirect
-    github.com/beito123/binary v3.0.1+incompatible // indirect
-    github.com/jkeys089/jserial v1.0.1 // indirect
-    github.com/pkg/errors v0.9.1 // indirect
+	github.com/BJTMastermind/Go-MC-Classic-Parser v0.2.2
+	github.com/BJTMastermind/go-nbt v1.2.4
+	github.com/BurntSushi/toml v1.5.0
+	github.com/akamensky/argparse v1.4.0
+	golang.org/x/image v0.18.0
+)
+
+require (
+	github.com/beito123/binary v3.0.1+incompatible // indirect
+	github.com/jkeys089/jserial v1.0.1 // indirect
+	github.com/pkg/errors v0.9.1 // indirect
 )
@@ -1,258 +1,369 @@
 package main
 
 import (
-    "bytes"
-    "compress/gzip"
-    "encoding/binary"
+    "context"
     "errors"
     "fmt"
     "os"
+    "path/filepath"
     "strings"
 
     "github.com/BJTMastermind/Classic-Converter/classic_converter"
-    "github.com/BJTMastermind/Go-MC-Classic-Parser"
-    "github.com/BJTMastermind/go-nbt"
     "github.com/akamensky/argparse"
 )
 
-func main() {
-    argparser := argparse.NewParser("Classic-Converter", "Convert classic worlds to indev worlds or schematics!")
+// logger gets every message, set up from the command line before anything gets converted and handed to the library with SetLogger
+var logger classic_converter.Logger = new(classic_converter.TextLogger).InitWithDefaults()
 
-    input := argparser.File("i", "input", os.O_RDONLY, os.ModePerm, &argparse.Options{Required: true, Help: "The classic world file to parse."})
-    format := argparser.String("f", "format", &argparse.Options{Required: true, Help: "The output format to use. One of \"indev_level\" or \"schematic\"."})
+func main() {
+    argparser := argparse.NewParser("Classic-Converter", "Convert classic worlds to indev worlds, schematics or classic server worlds!")
+
+    input := argparser.File("i", "input", os.O_RDONLY, os.ModePerm, &argparse.Options{Required: false, Help: "The classic world file, indev level or schematic to parse."})
+    format := argparser.String("f", "format", &argparse.Options{Required: false, Help: "The output format to use. One of " + quotedList(classic_converter.WritableFormats()) + "."})
+    listFormats := argparser.Flag("", "list-formats", &argparse.Options{Required: false, Help: "List every format that can be read or written and what it can hold."})
+    targetVersion := argparser.String("t", "target-version", &argparse.Options{Required: false, Help: "The classic version to downgrade blocks for when using the \"classic_v1\" (default 0.0.23a) or \"classic_v2\" (default 0.30) format."})
+    greedy := argparser.Flag("", "greedy", &argparse.Options{Required: false, Help: "Merge neighbouring faces of the same block into bigger faces when using the \"wavefront_obj\" or \"gltf_binary\" format."})
+    atlas := argparser.String("", "atlas", &argparse.Options{Required: false, Help: "Path to classic's terrain.png, used for block textures by the \"wavefront_obj\" (default terrain.png) and \"gltf_binary\" (vertex colors when not given) formats."})
+    region := argparser.String("", "region", &argparse.Options{Required: false, Help: "Two inclusive corners \"x1,y1,z1,x2,y2,z2\" of the region to export when using the \"stl_model\" format. (default whole world)"})
+    hollow := argparser.Int("", "hollow", &argparse.Options{Required: false, Default: 0, Help: "Hollow out the \"stl_model\" format, keeping walls this many blocks thick."})
+    basePlate := argparser.Int("", "base-plate", &argparse.Options{Required: false, Default: 0, Help: "Blocks of solid plate to add under the \"stl_model\" format."})
+    blockSize := argparser.Float("", "block-size", &argparse.Options{Required: false, Default: 1.0, Help: "Size of a block in millimeters when using the \"stl_model\" format."})
+    view := argparser.Selector("", "view", []string{"top", "isometric", "slices"}, &argparse.Options{Required: false, Default: "top", Help: "What to draw when using the \"render\" format. \"top\" draws a map of the world seen from above, \"isometric\" draws it from a corner and \"slices\" draws every Y layer with a grid and a block legend."})
+    animated := argparser.Flag("", "gif", &argparse.Options{Required: false, Help: "Write the \"slices\" view as one animated GIF instead of a PNG per layer."})
+    angle := argparser.Int("", "angle", &argparse.Options{Required: false, Default: 0, Help: "Which corner (0 to 3) the \"isometric\" view looks from."})
+    cutaway := argparser.Int("", "cutaway", &argparse.Options{Required: false, Default: -1, Help: "Leave out every block above this Y in the \"isometric\" view."})
+    scale := argparser.Int("", "scale", &argparse.Options{Required: false, Default: 4, Help: "Half the width of a block in pixels in the \"isometric\" view, the full width in the \"slices\" view."})
+    includeBlocks := argparser.Flag("", "include-blocks", &argparse.Options{Required: false, Help: "Add the blocks as runs of [block, count] when using the \"json\" format."})
+    ndjson := argparser.Flag("", "ndjson", &argparse.Options{Required: false, Help: "Write the \"json\" format as newline delimited JSON, one line for the header, every entity, the player and the blocks."})
+    blockTable := argparser.String("", "block-table", &argparse.Options{Required: false, Help: "The block table to map blocks to instead of the output format's, one of " + quotedList(classic_converter.BlockTables()) + "."})
+    blockOverrides := argparser.String("", "block-overrides", &argparse.Options{Required: false, Help: "A JSON or TOML file of blocks to swap for other blocks before they are mapped to the block table."})
+    strictBlocks := argparser.Flag("", "strict-blocks", &argparse.Options{Required: false, Help: "Fail instead of writing stone for blocks the block table has nothing for."})
+    skyColor := argparser.String("", "sky-color", &argparse.Options{Required: false, Help: "Sky color to write as RRGGBB, or \"default\" for the client's own. (default the world's)"})
+    fogColor := argparser.String("", "fog-color", &argparse.Options{Required: false, Help: "Fog color to write as RRGGBB, or \"default\" for the client's own. (default the world's)"})
+    cloudColor := argparser.String("", "cloud-color", &argparse.Options{Required: false, Help: "Cloud color to write as RRGGBB, or \"default\" for the client's own. (default the world's)"})
+    waterLevel := argparser.Int("", "water-level", &argparse.Options{Required: false, Default: -1, Help: "Water level to write, -1 keeps the world's."})
+    spawn := argparser.String("", "spawn", &argparse.Options{Required: false, Help: "Where players spawn. \"safe\" finds a safe spot, \"center\" is on top of the middle of the world and \"x,y,z\" is that block. (default the world's spawn)"})
+    metadata := argparser.Selector("", "metadata", []string{"none", "sidecar", "embed"}, &argparse.Options{Required: false, Default: "none", Help: "Keep everything about the world the output format can't hold. \"sidecar\" writes it to a .meta.json file next to the output, \"embed\" puts it inside the \"schematic\" or \"indev_level\" format. Either is read back when converting the output again."})
+    quiet := argparser.Flag("q", "quiet", &argparse.Options{Required: false, Help: "Only print errors."})
+    verbose := argparser.Flag("v", "verbose", &argparse.Options{Required: false, Help: "Also print the header fields of the world, how many entities it has and the entities the output format can't hold."})
+    logFormat := argparser.Selector("", "log-format", []string{"text", "json"}, &argparse.Options{Required: false, Default: "text", Help: "Print messages as plain text or as a line of JSON each."})
+    configFile := argparser.String("", "config", &argparse.Options{Required: false, Help: "A TOML file setting any of these arguments by their long name, arguments on the command line win. (default " + ternary(defaultConfigFile() != "", defaultConfigFile(), "none") + " when it exists)"})
+    preset := argparser.String("", "preset", &argparse.Options{Required: false, Help: "Also use the arguments under [presets.<name>] in the config."})
 
     err := argparser.Parse(os.Args)
     if err != nil {
-        fmt.Print(argparser.Usage(err))
-        return
+        fmt.Fprint(os.Stderr, argparser.Usage(err))
+        os.Exit(exitUsage)
     }
 
-    if !strings.HasSuffix(input.Name(), ".dat") && !strings.HasSuffix(input.Name(), ".mine") {
-        fmt.Print(argparser.Usage("Input file must be a classic world file. (.dat or .mine)"))
-        return
+    // Every argument except the input can come from the config
+    settings := map[string]setting{
+        "format": {value: format},
+        "target-version": {value: targetVersion},
+        "greedy": {value: greedy},
+        "atlas": {value: atlas, path: true},
+        "region": {value: region},
+        "hollow": {value: hollow},
+        "base-plate": {value: basePlate},
+        "block-size": {value: blockSize},
+        "view": {value: view, choices: []string{"top", "isometric", "slices"}},
+        "gif": {value: animated},
+        "angle": {value: angle},
+        "cutaway": {value: cutaway},
+        "scale": {value: scale},
+        "include-blocks": {value: includeBlocks},
+        "ndjson": {value: ndjson},
+        "block-table": {value: blockTable},
+        "block-overrides": {value: blockOverrides, path: true},
+        "strict-blocks": {value: strictBlocks},
+        "sky-color": {value: skyColor},
+        "fog-color": {value: fogColor},
+        "cloud-color": {value: cloudColor},
+        "water-level": {value: waterLevel},
+        "spawn": {value: spawn},
+        "metadata": {value: metadata, choices: []string{"none", "sidecar", "embed"}},
+        "quiet": {value: quiet},
+        "verbose": {value: verbose},
+        "log-format": {value: logFormat, choices: []string{"text", "json"}},
     }
-
-    if *format != "indev_level" && *format != "schematic" {
-        fmt.Print(argparser.Usage("Output format must be one of \"indev_level\" or \"schematic\"."))
-        return
+    if *configFile == "" {
+        if _, err := os.Stat(defaultConfigFile()); err == nil {
+            *configFile = defaultConfigFile()
+        }
+    }
+    if *configFile != "" {
+        err = loadConfig(*configFile, *preset, argparser, settings)
+    } else if *preset != "" {
+        err = fmt.Errorf("error: --preset %s needs a config, none was given and %s doesn't exist.", *preset, defaultConfigFile())
+    }
+    if err != nil {
+        fmt.Fprintln(os.Stderr, err)
+        os.Exit(exitUsage)
     }
 
-    if *format == "indev_level" {
-        fmt.Println("Converting to a indev level...")
-        err := convertToIndevLevel(*input)
-        if err != nil {
-            fmt.Println(err)
-        }
+    level := ternary(*verbose, classic_converter.LogDebug, classic_converter.LogInfo)
+    level = ternary(*quiet, classic_converter.LogError, level)
+    if *logFormat == "json" {
+        jsonLogger := new(classic_converter.JSONLogger).InitWithDefaults()
+        jsonLogger.Level = level
+        logger = jsonLogger
     } else {
-        fmt.Println("Converting to a schematic...")
-        err = convertToSchematic(*input)
-        if err != nil {
-            fmt.Println(err)
-        }
+        textLogger := new(classic_converter.TextLogger).InitWithDefaults()
+        textLogger.Level = level
+        logger = textLogger
     }
-}
+    classic_converter.SetLogger(logger)
 
-func convertToIndevLevel(inputFile os.File) (error) {
-    // Check that given file is a gzipped file
-    gzbytes, _ := os.ReadFile(inputFile.Name())
-
-    if gzMagic := binary.BigEndian.Uint16(gzbytes[0:2]); gzMagic != 0x1f8b {
-        return errors.New("Not a GZIP file.")
+    if *listFormats {
+        printFormats()
+        return
     }
 
-    // Get uncompressed file size
-    uncompressedSize := binary.LittleEndian.Uint32(gzbytes[len(gzbytes) - 4:])
-
-    // Decompress Gzip
-    gzReader, _ := gzip.NewReader(bytes.NewBuffer(gzbytes))
-    defer gzReader.Close()
-
-    var uncompressedBytes = make([]byte, uncompressedSize)
-    binary.Read(gzReader, binary.BigEndian, &uncompressedBytes)
-
-    reader := bytes.NewBuffer(uncompressedBytes)
-
-    magic := int32(binary.BigEndian.Uint32(reader.Next(4)))
-    version := reader.Next(1)[0]
-
-    indevLevel := new(classic_converter.IndevLevel).InitWithDefaults()
+    if !argumentGiven(argparser, "input") || *format == "" {
+        fmt.Fprint(os.Stderr, argparser.Usage("[-i|--input] and [-f|--format] are required"))
+        os.Exit(exitUsage)
+    }
 
-    // Check if a classic world
-    fmt.Println("Figuring out what classic version the world is...")
-    if magic != 0x271bb788 {
-        // Check if a pre classic world
-        if len(uncompressedBytes) != (256*256*64) {
-            return errors.New("error: Not a vaild Minecraft Pre-Classic save, Byte array is not equal to 4,194,304 bytes.")
-        }
+    outputFormat, ok := classic_converter.FormatByName(*format)
+    if !ok || outputFormat.NewWriter == nil {
+        fmt.Fprint(os.Stderr, argparser.Usage("Output format must be one of " + quotedList(classic_converter.WritableFormats()) + "."))
+        os.Exit(exitUnknownFormat)
+    }
 
-        for i := 0; i < (256*256*64); i++ {
-            if uncompressedBytes[i] < 0 || uncompressedBytes[i] > 49 {
-                return errors.New("error: Not a vaild Minecraft Pre-Classic save, Byte array contains block IDs greater then 49.")
-            }
+    options := new(classic_converter.Options).InitWithDefaults()
+    options.TargetVersion = *targetVersion
+    options.Greedy = *greedy
+    options.Atlas = *atlas
+    options.Region = *region
+    options.Hollow = *hollow
+    options.BasePlate = *basePlate
+    options.BlockSize = *blockSize
+    options.View = *view
+    options.Angle = *angle
+    options.Cutaway = *cutaway
+    options.Scale = *scale
+    options.Animated = *animated
+    options.IncludeBlocks = *includeBlocks
+    options.NDJSON = *ndjson
+    options.BlockTable = *blockTable
+    if *blockOverrides != "" {
+        options.BlockOverrides, err = classic_converter.ReadBlockOverrides(*blockOverrides)
+        if err != nil {
+            logger.Log(classic_converter.LogError, err.Error(), nil)
+            os.Exit(exitCode(err))
         }
-
-        // Vaild Pre-Classic save
-        fmt.Println("Found pre-classic world format!")
-
-        indevLevel.Blocks = classic_converter.ByteArray2Int8Array(uncompressedBytes)
-
-        indevLevel.FindSpawn()
-        indevLevel.WriteToFile(strings.NewReplacer(".dat", ".mclevel", ".mine", ".mclevel").Replace(inputFile.Name()))
-
-        return nil
+    }
+    options.Metadata = ternary(*metadata == "none", classic_converter.MetadataNone, *metadata)
+    options.SkyColor = *skyColor
+    options.FogColor = *fogColor
+    options.CloudColor = *cloudColor
+    options.WaterLevel = *waterLevel
+    options.Spawn = *spawn
+    options.StrictBlocks = *strictBlocks
+
+    // Progress bars would only clutter up logs and pipes
+    var progress classic_converter.Progress
+    if isTerminal(os.Stderr) && !*quiet && *logFormat == "text" {
+        progress = &progressBar{}
     }
 
-    if version != 0x01 && version != 0x02 {
-        return errors.New(fmt.Sprintf("error: Not a supported classic format version. Got %d, Expected 1 or 2\n", version))
+    logger.Log(classic_converter.LogInfo, fmt.Sprintf("Converting to %s...", outputFormat.Description), nil)
+    err = convert(*input, outputFormat, *options, progress)
+    if err != nil {
+        logger.Log(classic_converter.LogError, err.Error(), nil)
+        os.Exit(exitCode(err))
     }
+}
 
-    if version == 0x01 {
-        fmt.Println("Found classic version 1 world format!")
+// convert reads the input file as whatever format it is and writes it in the given format next to it.
+func convert(inputFile os.File, format classic_converter.Format, options classic_converter.Options, progress classic_converter.Progress) error {
+    // Options are checked before reading, so mistakes show up before a big world gets read
+    err := format.CheckOptions(options)
+    if err != nil {
+        return err
+    }
+    writer, suffix, err := format.NewWriter(options)
+    if err != nil {
+        return err
+    }
 
-        worldNameLength := binary.BigEndian.Uint16(reader.Next(2))
-        indevLevel.Name = string(reader.Next(int(worldNameLength)))
+    // Files are recognized by what is in them, so misnamed files still convert
+    inputFormat, detection, err := classic_converter.DetectFile(inputFile.Name())
+    if err != nil {
+        return err
+    }
+    if detection.Confidence > 0 {
+        logger.Log(classic_converter.LogInfo, fmt.Sprintf("Detected %s (%.0f%% sure)", detection.Kind, detection.Confidence * 100), nil)
+    } else {
+        logger.Log(classic_converter.LogInfo, fmt.Sprintf("Could not recognize the file, reading it as a %s by its extension", inputFormat.Description), nil)
+    }
 
-        creatorNameLength := binary.BigEndian.Uint16(reader.Next(2))
-        indevLevel.Author = string(reader.Next(int(creatorNameLength)))
+    level, err := inputFormat.ReadFileWithProgress(context.Background(), inputFile.Name(), progress)
+    if err != nil {
+        return err
+    }
 
-        indevLevel.CreatedOn = int64(binary.BigEndian.Uint16(reader.Next(8)))
-        indevLevel.Width = int16(binary.BigEndian.Uint16(reader.Next(2)))
-        indevLevel.Length = int16(binary.BigEndian.Uint16(reader.Next(2)))
-        indevLevel.Height = int16(binary.BigEndian.Uint16(reader.Next(2)))
-        indevLevel.Blocks = classic_converter.ByteArray2Int8Array(reader.Bytes())
+    level, err = classic_converter.ApplyEnvironment(level, options)
+    if err != nil {
+        return err
+    }
+    mapped, err := format.MapBlocks(level, options)
+    if err != nil {
+        return err
+    }
+    writer.FromLevel(mapped)
+    format.LogSkipped(level)
+    classic_converter.TrackProgress(context.Background(), writer, progress)
+    outputName := outputFileName(inputFile, suffix)
+    err = writer.WriteToFile(outputName)
+    if err != nil {
+        return err
+    }
+    if options.Metadata == classic_converter.MetadataSidecar {
+        return level.Metadata().WriteToFile(classic_converter.MetadataFilename(outputName))
+    }
 
-        indevLevel.FindSpawn()
-        indevLevel.WriteToFile(strings.NewReplacer(".dat", ".mclevel", ".mine", ".mclevel").Replace(inputFile.Name()))
-    } else if version == 0x02 {
-        fmt.Println("Found classic version 2 world format!")
+    return nil
+}
 
-        parser := new(mc_classic_parser.ClassicParser)
+// printFormats prints the format registry, what each format can be used for and what it can hold.
+func printFormats() {
+    fmt.Printf("%-16s %-4s %-20s %-44s %-12s %s\n", "Format", "Use", "Extensions", "Holds", "Blocks", "Description")
+    for _, format := range classic_converter.Formats() {
+        use := ternary(format.Reader != nil, "r", "-") + ternary(format.NewWriter != nil, "w", "-")
 
-        world, err := parser.ParseBytes(reader.Bytes())
-        if err != nil {
-            return err
+        holds := []string{"blocks"}
+        if format.Capabilities.DataValues {
+            holds = append(holds, "data")
         }
-
-        indevLevel.CreatedOn = world.CreateTime
-        indevLevel.Name = world.Name
-        indevLevel.Author = world.Creator
-        indevLevel.SkyColor = world.SkyColor
-        indevLevel.FogColor = world.FogColor
-        indevLevel.CloudColor = world.CloudColor
-        indevLevel.Width = int16(world.Width)
-        indevLevel.Length = int16(world.Depth)
-        indevLevel.Height = int16(world.Height)
-        indevLevel.Spawn = [3]int16{int16(world.XSpawn), int16(world.YSpawn), int16(world.ZSpawn)}
-        indevLevel.Blocks = world.Blocks
-
-        compoundEntities := []nbt.Compound{}
-        for _, entity := range world.Entities {
-            if entity.TextureName == "/char.png" {
-                continue
-            }
-
-            compoundEntity := classic_converter.ClassicEntity2Compound(entity, false)
-            compoundEntities = append(compoundEntities, compoundEntity)
+        if format.Capabilities.Entities {
+            holds = append(holds, "entities")
+        }
+        if format.Capabilities.Player {
+            holds = append(holds, "player")
+        }
+        if format.Capabilities.Metadata {
+            holds = append(holds, "metadata")
+        }
+        if size := format.Capabilities.MaxDimensions; size != [3]int16{} {
+            holds = append(holds, fmt.Sprintf("max %dx%dx%d", size[0], size[2], size[1]))
         }
-        compoundPlayer := classic_converter.ClassicPlayer2Compound(world.Player)
-        compoundEntities = append(compoundEntities, compoundPlayer)
-
-        indevLevel.Entities = compoundEntities
 
-        indevLevel.FindSpawn()
-        indevLevel.WriteToFile(strings.NewReplacer(".dat", ".mclevel", ".mine", ".mclevel").Replace(inputFile.Name()))
+        fmt.Printf("%-16s %-4s %-20s %-44s %-12s %s\n", format.Name, use, strings.Join(format.Extensions, " "), strings.Join(holds, ", "), ternary(format.BlockTable != "", format.BlockTable, "cpe"), format.Description)
     }
-    return nil
 }
 
-func convertToSchematic(inputFile os.File) (error) {
-    // Check that given file is a gzipped file
-    gzbytes, _ := os.ReadFile(inputFile.Name())
+// Exit codes, so scripts can tell what went wrong without reading the message
+const (
+    exitFailed = 1
+    exitUsage = 2
+    exitUnknownFormat = 3
+    exitUnsupportedVersion = 4
+    exitTruncated = 5
+    exitInvalidDimensions = 6
+    exitWriteFailed = 7
+    exitUnmappableBlock = 8
+)
 
-    if gzMagic := binary.BigEndian.Uint16(gzbytes[0:2]); gzMagic != 0x1f8b {
-        return errors.New("Not a GZIP file.")
+// exitCode picks the exit code for the kind of error, errors of no known kind exit with 1.
+func exitCode(err error) int {
+    kinds := []struct {
+        kind error
+        code int
+    }{
+        {classic_converter.ErrInvalidOption, exitUsage},
+        {classic_converter.ErrUnknownFormat, exitUnknownFormat},
+        {classic_converter.ErrUnsupportedVersion, exitUnsupportedVersion},
+        {classic_converter.ErrTruncated, exitTruncated},
+        {classic_converter.ErrInvalidDimensions, exitInvalidDimensions},
+        {classic_converter.ErrWriteFailed, exitWriteFailed},
+        {classic_converter.ErrUnmappableBlock, exitUnmappableBlock},
     }
-
-    // Get uncompressed file size
-    uncompressedSize := binary.LittleEndian.Uint32(gzbytes[len(gzbytes) - 4:])
-
-    // Decompress Gzip
-    gzReader, _ := gzip.NewReader(bytes.NewBuffer(gzbytes))
-    defer gzReader.Close()
-
-    var uncompressedBytes = make([]byte, uncompressedSize)
-    binary.Read(gzReader, binary.BigEndian, &uncompressedBytes)
-
-    reader := bytes.NewBuffer(uncompressedBytes)
-
-    magic := int32(binary.BigEndian.Uint32(reader.Next(4)))
-    version := reader.Next(1)[0]
-
-    schematic := new(classic_converter.Schematic).InitWithDefaults()
-
-    // Check if a classic world
-    fmt.Println("Figuring out what classic version the world is...")
-    if magic != 0x271bb788 {
-        // Check if a pre classic world
-        if len(uncompressedBytes) != (256*256*64) {
-            return errors.New("error: Not a vaild Minecraft Pre-Classic save, Byte array is not equal to 4,194,304 bytes.")
-        }
-
-        for i := 0; i < (256*256*64); i++ {
-            if uncompressedBytes[i] < 0 || uncompressedBytes[i] > 49 {
-                return errors.New("error: Not a vaild Minecraft Pre-Classic save, Byte array contains block IDs greater then 49.")
-            }
+    for _, kind := range kinds {
+        if errors.Is(err, kind.kind) {
+            return kind.code
         }
+    }
+    return exitFailed
+}
 
-        // Vaild Pre-Classic save
-        fmt.Println("Found pre-classic world format!")
-
-        schematic.Blocks = classic_converter.ByteArray2Int8Array(uncompressedBytes)
+// progressBar draws how far the current phase is as a bar on one line of the terminal, redrawn in place.
+type progressBar struct {
+    phase string
+    drawn string
+}
 
-        schematic.WriteToFile(strings.NewReplacer(".dat", ".schematic", ".mine", ".schematic").Replace(inputFile.Name()))
+func (progress_bar *progressBar) Report(phase string, processed int64, total int64) {
+    if phase != progress_bar.phase {
+        progress_bar.Finish()
+        progress_bar.phase = phase
     }
 
-    if version != 0x01 && version != 0x02 {
-        return errors.New(fmt.Sprintf("error: Not a supported classic format version. Got %d, Expected 1 or 2\n", version))
+    line := fmt.Sprintf("%-10s %d KB", phase, processed / 1024)
+    if total > 0 {
+        percent := int(ternary(processed < total, processed, total) * 100 / total)
+        filled := percent * 30 / 100
+        line = fmt.Sprintf("%-10s [%s%s] %3d%%", phase, strings.Repeat("#", filled), strings.Repeat("-", 30 - filled), percent)
+    }
+    if line != progress_bar.drawn {
+        fmt.Fprint(os.Stderr, "\r" + line)
+        progress_bar.drawn = line
     }
 
-    if version == 0x01 {
-        fmt.Println("Found classic version 1 world format!")
-
-        schematic.Width = int16(binary.BigEndian.Uint16(reader.Next(2)))
-        schematic.Length = int16(binary.BigEndian.Uint16(reader.Next(2)))
-        schematic.Height = int16(binary.BigEndian.Uint16(reader.Next(2)))
-        schematic.Blocks = classic_converter.ByteArray2Int8Array(reader.Bytes())
-
-        schematic.WriteToFile(strings.NewReplacer(".dat", ".schematic", ".mine", ".schematic").Replace(inputFile.Name()))
+    if total > 0 && processed >= total {
+        progress_bar.Finish()
+    }
+}
 
-    } else if version == 0x02 {
-        fmt.Println("Found classic version 2 world format!")
+// Finish moves past the bar, so whatever gets printed next starts on its own line
+func (progress_bar *progressBar) Finish() {
+    if progress_bar.drawn != "" {
+        fmt.Fprintln(os.Stderr)
+    }
+    progress_bar.phase = ""
+    progress_bar.drawn = ""
+}
 
-        parser := new(mc_classic_parser.ClassicParser)
+// isTerminal checks if the file is a terminal instead of a pipe or a regular file.
+func isTerminal(file *os.File) bool {
+    info, err := file.Stat()
+    return err == nil && info.Mode() & os.ModeCharDevice != 0
+}
 
-        world, err := parser.ParseBytes(reader.Bytes())
-        if err != nil {
-            return err
+// argumentGiven checks if the argument with the given long name was on the command line.
+func argumentGiven(argparser *argparse.Parser, name string) bool {
+    for _, argument := range argparser.GetArgs() {
+        if argument.GetLname() == name {
+            return argument.GetParsed()
         }
+    }
+    return false
+}
 
-        schematic.Width = int16(world.Width)
-        schematic.Length = int16(world.Depth)
-        schematic.Height = int16(world.Height)
-        schematic.Blocks = world.Blocks
-
-        compoundEntities := []nbt.Compound{}
-        for _, entity := range world.Entities {
-            if entity.TextureName == "/char.png" {
-                continue
-            }
+// quotedList joins the names as "a", "b" or "c".
+func quotedList(names []string) string {
+    quoted := make([]string, len(names))
+    for i, name := range names {
+        quoted[i] = "\"" + name + "\""
+    }
+    if len(quoted) < 2 {
+        return strings.Join(quoted, "")
+    }
+    return strings.Join(quoted[:len(quoted) - 1], ", ") + " or " + quoted[len(quoted) - 1]
+}
 
-            compoundEntity := classic_converter.ClassicEntity2Compound(entity, true)
-            compoundEntities = append(compoundEntities, compoundEntity)
-        }
-        schematic.Entities = compoundEntities
+func ternary[T any](condition bool, a T, b T) T {
+    if condition {
+        return a
+    }
+    return b
+}
 
-        schematic.WriteToFile(strings.NewReplacer(".dat", ".schematic", ".mine", ".schematic").Replace(inputFile.Name()))
+// outputFileName swaps the extension of the input file for the given one, without ever overwriting the input file.
+func outputFileName(inputFile os.File, extension string) string {
+    base := strings.TrimSuffix(inputFile.Name(), filepath.Ext(inputFile.Name()))
+    if base + extension == inputFile.Name() {
+        return base + "_converted" + extension
     }
-    return nil
+    return base + extension
 }